@@ -9,6 +9,8 @@ require (
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/zap v1.24.0
 	golang.org/x/net v0.2.0
+	golang.org/x/text v0.14.0
+	golang.org/x/time v0.2.0
 )
 
 require (
@@ -11,7 +11,6 @@ import (
 	"strings"
 	"sync"
 	"syscall"
-	"time"
 
 	"github.com/bool64/ctxd"
 
@@ -36,6 +35,10 @@ const (
 	CodeErrBadArgs
 	// CodeErrOutput indicates that the program could not write to output.
 	CodeErrOutput
+	// CodeErrBrokenLinks indicates that the program found broken links in the link checking mode.
+	CodeErrBrokenLinks
+	// CodeErrReadSitemap indicates that the program could not read the sitemap.
+	CodeErrReadSitemap
 )
 
 const (
@@ -51,6 +54,7 @@ type ExitCode int
 // It will take only the first valid source as an input. The source types are:
 // - []string: A list of URLs.
 // - string: A file path that contains a list of URLs, one on each line.
+// - Sitemap: The URL of a sitemap, a sitemap index or a site whose robots.txt has the sitemaps.
 // - io.ReadCloser: A reader that contains a list of URLs, one on each line.
 // - io.Reader: A reader that contains a list of URLs, one on each line.
 //
@@ -69,33 +73,52 @@ func Run(cfg Config, inputSources ...any) ExitCode {
 	log := initLogger(cfg.VerbosityLevel, cfg.ErrWriter)
 
 	// Configure crawler.
-	c, err := initCrawler(cfg.NumWorkers, cfg.Timeout, log)
+	c, err := initCrawler(cfg, log)
 	if err != nil {
 		_, _ = fmt.Fprintln(cfg.ErrWriter, err.Error())
 
 		return CodeErrBadArgs
 	}
 
+	// The urls of a sitemap are read by the crawler, with the same robots.txt compliance and per-host limits as the pages.
+	sitemap, isSitemap := inputSource.(*sitemapInput)
+	if isSitemap {
+		sitemap.start(c)
+	}
+
 	// Configure resultWriter.
-	var writeResult resultWriter
-
-	if cfg.VerbosityLevel > VerbosityLevelSilent {
-		// When the verbosity level is not silent, the log messages will be printed to the output randomly.
-		// And the application cannot guarantee the prettified output to human users because stdout and stderr are visualized on the same screen.
-		// This is not a problem to machines because the log messages are sent to stderr which is another file descriptor.
-		//
-		// Therefore, we will buffer the output and send at once when all the links are processed.
-		writeResult = bufferedJSONResultWriter(cfg.OutWriter, cfg.PrettyOutput, log)
-	} else {
-		// When the verbosity level is silent, there is no log messages to print. It would be great to see the progress of the program rather than waiting till
-		// the end. Therefore, the program could print out the result as soon as it is ready.
-		writeResult = unbufferedJSONResultWriter(cfg.OutWriter, cfg.ErrWriter, cfg.PrettyOutput)
+	writeResult, err := initResultWriter(cfg, log)
+	if err != nil {
+		_, _ = fmt.Fprintln(cfg.ErrWriter, err.Error())
+
+		return CodeErrBadArgs
+	}
+
+	// The skipped sources are dropped after the reordering, because the ordered mode needs a result for every input source.
+	writeResult = crawledResultWriter(writeResult)
+
+	if cfg.OrderedOutput {
+		writeResult = orderedResultWriter(writeResult, reorderBufferSize)
 	}
 
 	// Use buffered channel to avoid resource saturation.
 	publishSource := bufferedSourcePublisher(cfg.NumWorkers, log)
 
-	return doCrawl(c, publishSource, writeResult, inputSource, log)
+	code = doCrawl(c, publishSource, writeResult, inputSource, log)
+
+	if isSitemap {
+		// The sitemap is read to the end, unless the crawling stopped early. Closing the input stops the reading in that case.
+		_ = sitemap.Close() // nolint: errcheck
+
+		// The error of a crawling that stopped early is already reported.
+		if err := sitemap.wait(); err != nil && (code == CodeOK || code == CodeErrBrokenLinks) {
+			_, _ = fmt.Fprintln(cfg.ErrWriter, err.Error())
+
+			return CodeErrReadSitemap
+		}
+	}
+
+	return code
 }
 
 // initLogger returns a new logger.
@@ -128,6 +151,7 @@ func initLogger(level VerbosityLevel, errWriter io.Writer) ctxd.Logger {
 // It accepts a list of input sources. The source types are:
 // - []string: A list of URLs. If the list is empty, it is ignored.
 // - string: A file path that contains a list of URLs, one on each line. If the path is empty, it is ignored.
+// - Sitemap: The URL of a sitemap, a sitemap index or a site whose robots.txt has the sitemaps. If the URL is empty, it is ignored.
 // - io.ReadCloser: A reader that contains a list of URLs, one on each line.
 // - io.Reader: A reader that contains a list of URLs, one on each line.
 //
@@ -161,6 +185,13 @@ func initInputSource(sources ...any) (io.ReadCloser, ExitCode, error) {
 
 			return f, CodeOK, nil
 
+		case Sitemap:
+			if len(s) == 0 {
+				continue
+			}
+
+			return newSitemapInput(string(s)), CodeOK, nil
+
 		case io.ReadCloser:
 			return s, CodeOK, nil
 
@@ -175,30 +206,202 @@ func initInputSource(sources ...any) (io.ReadCloser, ExitCode, error) {
 	return nil, CodeErrNoInputSource, errors.New("no input source")
 }
 
-// initCrawler initiates a new crawler.LinkCrawler for counting links.
+// initCrawler initiates a new crawler.HTTPLinkCrawler for counting links.
 //
-// The function returns an error if the number of workers is smaller than 1 or greater than the maximum number of workers.
+// The function returns an error if the number of workers is smaller than 1 or greater than the maximum number of workers, or if the depth, the number of
+// pages, the per-host limits, the maximum attempts or the maximum redirects are negative, or if the request options are invalid, see
+// initRequestOptions.
 //
-// nolint: goerr113 // Error will be printed out.
-func initCrawler(numWorkers int, timeout time.Duration, log ctxd.Logger) (crawler.LinkCrawler, error) {
-	if numWorkers < 1 {
+// nolint: cyclop,goerr113 // Error will be printed out.
+func initCrawler(cfg Config, log ctxd.Logger) (*crawler.HTTPLinkCrawler, error) {
+	if cfg.NumWorkers < 1 {
 		return nil, errors.New(`number of workers must be greater than 0`)
-	} else if numWorkers > maxNumWorkers {
+	} else if cfg.NumWorkers > maxNumWorkers {
 		return nil, fmt.Errorf(`maximum workers is %d`, maxNumWorkers)
 	}
 
-	c := crawler.NewHTTPLinkCrawler(
+	if cfg.MaxDepth < 0 {
+		return nil, errors.New(`depth must not be negative`)
+	}
+
+	if cfg.MaxPages < 0 {
+		return nil, errors.New(`maximum pages must not be negative`)
+	}
+
+	if cfg.HostRateLimit < 0 || cfg.HostBurst < 0 {
+		return nil, errors.New(`host rate limit must not be negative`)
+	}
+
+	if cfg.HostConcurrency < 0 {
+		return nil, errors.New(`host concurrency must not be negative`)
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return nil, errors.New(`maximum attempts must not be negative`)
+	}
+
+	if cfg.MaxRedirects != nil && *cfg.MaxRedirects < 0 {
+		return nil, errors.New(`maximum redirects must not be negative`)
+	}
+
+	if cfg.MaxBodySize < 0 {
+		return nil, errors.New(`maximum body size must not be negative`)
+	}
+
+	opts := []crawler.HTTPLinkCrawlerOption{
 		crawler.WithLinkCollectors(map[string]collector.LinkCollector{
 			"text/html":  collector.NewHTMLLinkCollector(),
 			"text/plain": collector.NewTextLinkCollector(),
+			"text/css":   collector.NewCSSLinkCollector(),
 		}),
 		crawler.WithLinkCollector(collector.NewJSONLinkCollector(), "application/json", "text/x-json"),
-		crawler.WithClientTimeout(timeout),
-		crawler.WithNumWorkers(numWorkers),
+		crawler.WithLinkCollector(collector.NewXMLLinkCollector(), "application/rss+xml", "application/atom+xml", "application/xml", "text/xml"),
+		crawler.WithClientTimeout(cfg.Timeout),
+		crawler.WithNumWorkers(cfg.NumWorkers),
+		crawler.WithMaxDepth(cfg.MaxDepth),
+		crawler.WithMaxPages(cfg.MaxPages),
+		crawler.WithRobotsTxt(cfg.RespectRobotsTxt),
+		crawler.WithRespectNoFollow(cfg.RespectNoFollow),
+		crawler.WithHostRateLimit(cfg.HostRateLimit, cfg.HostBurst),
+		crawler.WithHostConcurrency(cfg.HostConcurrency),
+		crawler.WithRetryPolicy(crawler.RetryPolicy{MaxAttempts: cfg.MaxAttempts}),
+		crawler.WithFollowRedirects(!cfg.NoFollowRedirects),
+		crawler.WithLinkCheck(cfg.CheckLinks),
+		crawler.WithMaxBodySize(cfg.MaxBodySize, cfg.TruncateBody),
 		crawler.WithLogger(log),
-	)
+	}
+
+	if cfg.MaxRedirects != nil {
+		opts = append(opts, crawler.WithMaxRedirects(*cfg.MaxRedirects))
+	}
+
+	requestOpts, err := initRequestOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, requestOpts...)
+
+	return crawler.NewHTTPLinkCrawler(opts...), nil
+}
+
+// initResultWriter initiates a new resultWriter for the output format.
+//
+// The function returns an error if the output format or the output mode is not supported, if a column is unknown, or if the columns or the link checking
+// mode are not supported by the output format.
+//
+// nolint: cyclop,goerr113 // Error will be printed out.
+func initResultWriter(cfg Config, log ctxd.Logger) (resultWriter, error) {
+	outCfg := outputConfig{
+		pretty:       cfg.PrettyOutput,
+		checkLinks:   cfg.CheckLinks,
+		includeLinks: cfg.IncludeLinks,
+	}
+
+	buffered, err := isBufferedOutput(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Columns) > 0 && cfg.OutputFormat != OutputFormatCSV && cfg.OutputFormat != OutputFormatTSV {
+		return nil, errors.New(`columns are only supported by the csv and tsv formats`)
+	}
+
+	var newWriter func(out io.Writer) resultWriter
+
+	switch cfg.OutputFormat {
+	case "", OutputFormatJSON:
+		if buffered {
+			return bufferedJSONResultWriter(cfg.OutWriter, outCfg, log), nil
+		}
+
+		return unbufferedJSONResultWriter(cfg.OutWriter, cfg.ErrWriter, outCfg), nil
+
+	case OutputFormatNDJSON:
+		newWriter = func(out io.Writer) resultWriter {
+			return ndjsonResultWriter(out, cfg.ErrWriter, outCfg)
+		}
+
+	case OutputFormatCSV, OutputFormatTSV:
+		if cfg.CheckLinks {
+			return nil, fmt.Errorf(`link checking mode is not supported by the %s format`, cfg.OutputFormat)
+		}
+
+		columns, err := parseCSVColumns(cfg.Columns, cfg.IncludeLinks)
+		if err != nil {
+			return nil, err
+		}
+
+		comma := ','
+
+		if cfg.OutputFormat == OutputFormatTSV {
+			comma = '\t'
+		}
+
+		newWriter = func(out io.Writer) resultWriter {
+			return csvResultWriter(out, cfg.ErrWriter, comma, columns, cfg.CSVHeader)
+		}
+
+	default:
+		return nil, fmt.Errorf(`unsupported output format: %s`, cfg.OutputFormat)
+	}
+
+	if buffered {
+		return bufferedResultWriter(cfg.OutWriter, log, newWriter), nil
+	}
+
+	return newWriter(cfg.OutWriter), nil
+}
+
+// isBufferedOutput checks whether the results should be buffered according to the output mode.
+//
+// In the auto mode, when the output and the error output are the same terminal, the log messages will be printed to the output randomly. And the
+// application cannot guarantee the prettified output to human users because stdout and stderr are visualized on the same screen. Therefore, we will buffer
+// the output and send at once when all the links are processed.
+//
+// Otherwise, for example when the output is piped to another program or the error output is redirected to a file, it would be great to see the progress of
+// the program rather than waiting till the end. Therefore, the program could print out the result as soon as it is ready.
+//
+// nolint: goerr113 // Error will be printed out.
+func isBufferedOutput(cfg Config) (bool, error) {
+	switch cfg.OutputMode {
+	case "", OutputModeAuto:
+		return isSameTerminal(cfg.OutWriter, cfg.ErrWriter), nil
+
+	case OutputModeBuffered:
+		return true, nil
+
+	case OutputModeStream:
+		return false, nil
+	}
+
+	return false, fmt.Errorf(`unsupported output mode: %s`, cfg.OutputMode)
+}
+
+// isSameTerminal checks whether the two writers are the same terminal.
+//
+// A terminal is a character device. When stdout and stderr are not redirected, they are the same device.
+func isSameTerminal(w1, w2 io.Writer) bool {
+	f1, ok1 := w1.(*os.File)
+	f2, ok2 := w2.(*os.File)
+
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	fi1, err := f1.Stat()
+	if err != nil {
+		// Just ignore because we do not know if it is a terminal or not.
+		return false
+	}
+
+	fi2, err := f2.Stat()
+	if err != nil {
+		// Just ignore because we do not know if it is a terminal or not.
+		return false
+	}
 
-	return c, nil
+	return (fi1.Mode()&os.ModeCharDevice) != 0 && os.SameFile(fi1, fi2)
 }
 
 // doCrawl crawls the input source and prints the result to the output writer.
@@ -78,7 +78,7 @@ func Test_Run_InputPipe_Success(t *testing.T) {
 		NumWorkers: 1,
 	}, f)
 
-	expected := fmt.Sprintf(`[{"page_url":"%s/path1","internal_links_num":1,"external_links_num":0,"success":true,"error":null}]`, srv.URL())
+	expected := fmt.Sprintf(`[{"page_url":"%s/path1","index":0,"internal_links_num":1,"external_links_num":0,"success":true,"error":null,"attempts":1}]`, srv.URL())
 
 	assert.Equal(t, expected, strings.Trim(outBuf.String(), "\n"))
 	assert.Empty(t, errBuf.String())
@@ -84,7 +84,7 @@ func Test_Run_SigTerm(t *testing.T) {
 	wg.Wait()
 
 	// There should be only one result because the publisher is stopped when the context is canceled.
-	expected := fmt.Sprintf(`[{"page_url":"%s/path1","internal_links_num":0,"external_links_num":0,"success":false,"error":"operation canceled"}]`, srv.URL())
+	expected := fmt.Sprintf(`[{"page_url":"%s/path1","index":0,"internal_links_num":0,"external_links_num":0,"success":false,"error":"operation canceled","attempts":1}]`, srv.URL())
 
 	assert.Equal(t, expected, strings.Trim(outBuf.String(), "\r\n"))
 	assert.NotEmpty(t, errBuf.String())
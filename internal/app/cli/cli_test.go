@@ -58,3 +58,8 @@ func srvRequests(srv *httpmock.Server, numRequests int) []string {
 
 	return result
 }
+
+// intPtr returns a pointer to the int, for the optional int options of the config.
+func intPtr(i int) *int {
+	return &i
+}
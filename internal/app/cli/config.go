@@ -17,6 +17,33 @@ const (
 	VerbosityLevelDebug
 )
 
+// OutputFormat is the format of the output.
+type OutputFormat string
+
+const (
+	// OutputFormatJSON is a JSON array of the results.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatNDJSON is a JSON object per line for each result, without a wrapping array.
+	OutputFormatNDJSON OutputFormat = "ndjson"
+	// OutputFormatCSV is a comma separated row for each result.
+	OutputFormatCSV OutputFormat = "csv"
+	// OutputFormatTSV is a tab separated row for each result.
+	OutputFormatTSV OutputFormat = "tsv"
+)
+
+// OutputMode is the mode of writing the results to the output.
+type OutputMode string
+
+const (
+	// OutputModeAuto buffers the results when the output and the error output are the same terminal, so that the log messages do not mess up the results.
+	// Otherwise, it streams the results.
+	OutputModeAuto OutputMode = "auto"
+	// OutputModeBuffered keeps the results in memory and writes them to the output at the end of the process.
+	OutputModeBuffered OutputMode = "buffered"
+	// OutputModeStream writes the results to the output as soon as they are ready.
+	OutputModeStream OutputMode = "stream"
+)
+
 // Config is the configuration of the application.
 type Config struct {
 	OutWriter io.Writer // The stream that will receive the results
@@ -25,5 +52,40 @@ type Config struct {
 	NumWorkers     int            // The number of workers that the crawler could run.
 	Timeout        time.Duration  // The timeout of the http client of the crawler.
 	PrettyOutput   bool           // Disable JSON prettifier.
+	IncludeLinks   bool           // Include the internal and external links in the results, not only the numbers of them.
+	OutputFormat   OutputFormat   // The format of the output. Empty means OutputFormatJSON.
+	Columns        []string       // The columns of the csv and tsv formats. Empty means the default columns.
+	CSVHeader      bool           // Write a header row in the csv and tsv formats.
+	OutputMode     OutputMode     // The mode of writing the results to the output. Empty means OutputModeAuto.
+	OrderedOutput  bool           // Write the results in the order of the input sources.
 	VerbosityLevel VerbosityLevel // The verbosity level of the tool.
+
+	MaxDepth int // The maximum depth for crawling internal links recursively. Zero disables the recursive mode.
+	MaxPages int // The maximum number of pages to crawl. Zero means unlimited.
+
+	RespectRobotsTxt bool // Skip the urls that are disallowed by the robots.txt of their hosts.
+	RespectNoFollow  bool // Do not follow the nofollow links and the links of the nofollow pages in the recursive mode.
+
+	HostRateLimit   float64 // The number of requests per second to each host. Zero means unlimited.
+	HostBurst       int     // The maximum number of requests that could be sent at once to each host.
+	HostConcurrency int     // The maximum number of concurrent requests to each host. Zero means unlimited.
+
+	MaxAttempts int // The maximum number of attempts for a request that failed due to transient errors. Zero or one means no retry.
+
+	MaxRedirects      *int // The maximum number of redirects to follow. Nil means the default of the crawler, which is 10. Zero fails on any redirect.
+	NoFollowRedirects bool // Report the redirects without following them.
+
+	MaxBodySize  int64 // The maximum size of a response body to read, after decoding. Zero means unlimited.
+	TruncateBody bool  // Collect the links of the first MaxBodySize bytes of a larger body instead of failing.
+
+	CheckLinks bool // Check the health of every collected link and report the broken ones.
+
+	UserAgent    string   // The user agent of the requests. Empty means the default of the crawler.
+	Headers      []string // The headers of every request, in the form of `Name: value`. They are sent to every host, including the external links.
+	CookieFile   string   // The path to a Netscape cookie file, the cookies.txt format of curl and wget. Empty means no cookie.
+	BasicAuth    []string // The basic authentication of the hosts, in the form of `HOST=USER:PASSWORD`. They are only sent to their hosts.
+	BearerTokens []string // The bearer tokens of the hosts, in the form of `HOST=TOKEN`. They are only sent to their hosts.
+
+	Proxy   string   // The url of the proxy, with the scheme `http`, `https` or `socks5`. Empty means the proxy of the environment variables.
+	NoProxy []string // The hosts that are requested without the proxy, in the format of the NO_PROXY environment variable.
 }
@@ -6,18 +6,22 @@ import (
 	"io"
 
 	"github.com/bool64/ctxd"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
 )
 
 // sourcePublisher is a function that reads from a source and publishes the results to a channel.
-type sourcePublisher func(ctx context.Context, source io.Reader) <-chan string
+type sourcePublisher func(ctx context.Context, source io.Reader) <-chan crawler.Source
 
 // bufferedSourcePublisher creates a new source publisher that reads from a source and publishes the results to a buffered channel.
 //
 // The buffer size is double the number of workers. This is a fair balance between resource saturation and performance.
+//
+// Every source is published with its position in the input, starting from 0, so that the results can be reordered by the positions of their sources.
 func bufferedSourcePublisher(numWorkers int, log ctxd.Logger) sourcePublisher {
-	return func(ctx context.Context, source io.Reader) <-chan string {
+	return func(ctx context.Context, source io.Reader) <-chan crawler.Source {
 		bufSize := numWorkers * 2 // nolint: gomnd // Buffer size is double the number of workers.
-		linksCh := make(chan string, bufSize)
+		linksCh := make(chan crawler.Source, bufSize)
 
 		log.Debug(ctx, "started buffered publisher", "buffer_size", bufSize)
 
@@ -25,6 +29,7 @@ func bufferedSourcePublisher(numWorkers int, log ctxd.Logger) sourcePublisher {
 			defer close(linksCh)
 
 			s := bufio.NewScanner(source)
+			index := 0
 
 		process:
 			for {
@@ -41,9 +46,10 @@ func bufferedSourcePublisher(numWorkers int, log ctxd.Logger) sourcePublisher {
 
 					link := s.Text()
 
-					log.Debug(ctx, "publishing source", "source", link)
+					log.Debug(ctx, "publishing source", "source", link, "index", index)
 
-					linksCh <- link
+					linksCh <- crawler.Source{URL: link, Index: index}
+					index++
 				}
 			}
 
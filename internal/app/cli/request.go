@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+// initRequestOptions returns the crawler options for the user agent, the custom headers, the cookies, the credentials and the proxy of the requests.
+//
+// The function returns an error if a header is not in the form of `Name: value`, if a credential is not in the form of `HOST=USER:PASSWORD` or
+// `HOST=TOKEN`, if the cookie file could not be read, or if the proxy is not a valid url. The values of the credentials are never printed out.
+//
+// nolint: cyclop,goerr113 // Error will be printed out.
+func initRequestOptions(cfg Config) ([]crawler.HTTPLinkCrawlerOption, error) {
+	var opts []crawler.HTTPLinkCrawlerOption
+
+	if cfg.UserAgent != "" {
+		opts = append(opts, crawler.WithUserAgent(cfg.UserAgent))
+	}
+
+	if len(cfg.Headers) > 0 {
+		headers := make(http.Header, len(cfg.Headers))
+
+		for _, h := range cfg.Headers {
+			name, value, ok := strings.Cut(h, ":")
+			name = strings.TrimSpace(name)
+
+			if !ok || name == "" || strings.ContainsAny(name, " \t") {
+				return nil, fmt.Errorf(`invalid header %q, expected "Name: value"`, h)
+			}
+
+			headers.Add(name, strings.TrimSpace(value))
+		}
+
+		opts = append(opts, crawler.WithHeaders(headers))
+	}
+
+	for _, a := range cfg.BasicAuth {
+		host, credentials, ok := strings.Cut(a, "=")
+		username, password, hasPassword := strings.Cut(credentials, ":")
+
+		if !ok || host == "" || !hasPassword || username == "" {
+			return nil, fmt.Errorf(`invalid basic auth of host %q, expected "HOST=USER:PASSWORD"`, host)
+		}
+
+		opts = append(opts, crawler.WithBasicAuth(host, username, password))
+	}
+
+	for _, a := range cfg.BearerTokens {
+		host, token, ok := strings.Cut(a, "=")
+
+		if !ok || host == "" || token == "" {
+			return nil, fmt.Errorf(`invalid bearer token of host %q, expected "HOST=TOKEN"`, host)
+		}
+
+		opts = append(opts, crawler.WithBearerToken(host, token))
+	}
+
+	if cfg.CookieFile != "" {
+		jar, err := readCookieFile(cfg.CookieFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, crawler.WithCookieJar(jar))
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := parseProxyURL(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, crawler.WithProxy(proxyURL))
+	}
+
+	if len(cfg.NoProxy) > 0 {
+		opts = append(opts, crawler.WithNoProxy(cfg.NoProxy...))
+	}
+
+	return opts, nil
+}
+
+// readCookieFile reads the cookies of a Netscape cookie file.
+func readCookieFile(path string) (http.CookieJar, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("could not open cookie file: %w", err)
+	}
+
+	defer f.Close() // nolint: errcheck
+
+	return crawler.NewNetscapeCookieJar(f)
+}
+
+// parseProxyURL parses the url of a proxy. If the scheme is missing, default to http.
+//
+// nolint: goerr113 // Error will be printed out.
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	// The error of url.Parse is not printed out because it has the credentials of the proxy.
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, errors.New("invalid proxy url")
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", u.Scheme)
+}
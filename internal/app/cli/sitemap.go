@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Sitemap is the url of a sitemap or a sitemap index to read the urls from. If it is the root or the robots.txt of a site, for example:
+// `https://example.com/`, the sitemaps are discovered from the robots.txt of the site.
+type Sitemap string
+
+// sitemapReader reads the urls of the pages in a sitemap.
+type sitemapReader interface {
+	ReadSitemap(ctx context.Context, sitemapURL string, fn func(pageURL string) error) error
+}
+
+var _ io.ReadCloser = (*sitemapInput)(nil)
+
+// sitemapInput is an input source that contains the urls of the pages in a sitemap, one on each line.
+//
+// The sitemap is read by a crawler, so the input is empty until it is started. The urls are streamed to the reader as soon as they are read, and the
+// reading is stopped when the input is closed.
+type sitemapInput struct {
+	url    string
+	ctx    context.Context
+	cancel context.CancelFunc
+	r      *io.PipeReader
+	w      *io.PipeWriter
+
+	// done is closed when the reading is finished, then err is the error of the reading, if any.
+	done chan struct{}
+	err  error
+}
+
+// Read reads the urls of the pages in the sitemap.
+func (i *sitemapInput) Read(p []byte) (int, error) {
+	return i.r.Read(p)
+}
+
+// Close stops reading the sitemap.
+func (i *sitemapInput) Close() error {
+	i.cancel()
+
+	return i.r.Close()
+}
+
+// start reads the sitemap in the background. If the sitemap could not be read, the input ends with the urls that have been read, and the error is returned
+// by wait.
+func (i *sitemapInput) start(c sitemapReader) {
+	go func() {
+		defer close(i.done)
+
+		err := c.ReadSitemap(i.ctx, i.url, func(pageURL string) error {
+			_, err := io.WriteString(i.w, pageURL+"\n")
+
+			return err
+		})
+		if err != nil {
+			i.err = fmt.Errorf("could not read sitemap: %w", err)
+		}
+
+		_ = i.w.Close() // nolint: errcheck // Closing a pipe writer always returns nil.
+	}()
+}
+
+// wait waits until the reading is finished and returns its error. The input should be read to the end or closed before, otherwise the reading is blocked.
+func (i *sitemapInput) wait() error {
+	<-i.done
+
+	return i.err
+}
+
+// newSitemapInput creates a new input source for the urls of the pages in a sitemap.
+func newSitemapInput(sitemapURL string) *sitemapInput {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+
+	return &sitemapInput{
+		url:    sitemapURL,
+		ctx:    ctx,
+		cancel: cancel,
+		r:      r,
+		w:      w,
+		done:   make(chan struct{}),
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/bool64/ctxd"
 
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
 	"github.com/nhatthm/go-playground-20221201/internal/crawler"
 )
 
@@ -18,42 +20,172 @@ const jsonIndent = "  "
 // resultWriter is a function that writes the results of a crawler to a writer.
 type resultWriter func(results <-chan crawler.LinkCrawlerResult) ExitCode
 
+// outputConfig is the configuration of the output of a result writer.
+type outputConfig struct {
+	pretty       bool // Prettify the JSON output.
+	checkLinks   bool // Write the checked links along with the pages.
+	includeLinks bool // Include the link lists in the results.
+}
+
 // nolint: tagliatelle
 type crawlerResult struct {
-	PageURL          string  `json:"page_url"`
-	NumInternalLinks int     `json:"internal_links_num"`
-	NumExternalLinks int     `json:"external_links_num"`
-	Success          bool    `json:"success"`
-	Error            *string `json:"error"`
+	PageURL          string     `json:"page_url"`
+	Index            *int       `json:"index,omitempty"`
+	NumInternalLinks int        `json:"internal_links_num"`
+	NumExternalLinks int        `json:"external_links_num"`
+	Success          bool       `json:"success"`
+	Error            *string    `json:"error"`
+	Attempts         int        `json:"attempts"`
+	ParentURL        string     `json:"parent_url,omitempty"`
+	Depth            int        `json:"depth,omitempty"`
+	CanonicalURL     string     `json:"canonical_url,omitempty"`
+	NoFollow         bool       `json:"nofollow,omitempty"`
+	Truncated        bool       `json:"truncated,omitempty"`
+	InternalLinksRel *relCounts `json:"internal_links_rel,omitempty"`
+	ExternalLinksRel *relCounts `json:"external_links_rel,omitempty"`
+	Redirects        []redirect `json:"redirects,omitempty"`
+	InternalLinks    []link     `json:"internal_links,omitempty"`
+	ExternalLinks    []link     `json:"external_links,omitempty"`
+}
+
+// nolint: tagliatelle
+type link struct {
+	URL       string   `json:"url"`
+	Tag       string   `json:"tag,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Rel       []string `json:"rel,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	AriaLabel string   `json:"aria_label,omitempty"`
+}
+
+// relCounts is the number of links by rel category. It is only in the output if there is a nofollow, sponsored or ugc link.
+//
+// nolint: tagliatelle
+type relCounts struct {
+	Followed  int `json:"followed"`
+	NoFollow  int `json:"nofollow"`
+	Sponsored int `json:"sponsored"`
+	UGC       int `json:"ugc"`
+}
+
+// nolint: tagliatelle
+type redirect struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"`
+}
+
+// nolint: tagliatelle
+type linkResult struct {
+	URL        string   `json:"url"`
+	StatusCode int      `json:"status_code"`
+	LatencyMs  int64    `json:"latency_ms"`
+	Success    bool     `json:"success"`
+	Error      *string  `json:"error"`
+	Referrers  []string `json:"referrers,omitempty"`
+}
+
+// linkCheckReport is the output in the link checking mode.
+type linkCheckReport struct {
+	Pages []crawlerResult `json:"pages"`
+	Links []linkResult    `json:"links"`
+}
+
+// crawledResultWriter creates a new result writer that passes only the results of the crawled sources to the next writer. The results of the skipped
+// sources are dropped, they only mark the positions of the sources in the input for the ordered mode.
+func crawledResultWriter(next resultWriter) resultWriter {
+	return func(results <-chan crawler.LinkCrawlerResult) ExitCode {
+		crawled := make(chan crawler.LinkCrawlerResult)
+
+		go func() {
+			defer close(crawled)
+
+			for r := range results {
+				if !r.Skipped {
+					crawled <- r
+				}
+			}
+		}()
+
+		code := next(crawled)
+
+		// The next writer may stop early in case of error, drain the remaining results so that the filtering stops.
+		go func() {
+			for range crawled { // nolint: revive // Drain the channel.
+			}
+		}()
+
+		return code
+	}
+}
+
+// bufferedResultWriter creates a new result writer that writes the results to memory with the given writer, and then the output at the end of the process.
+//
+// In case of error while writing to the output, the error will be logged and the process will stop with exit code CodeErrOutput.
+func bufferedResultWriter(out io.Writer, log ctxd.Logger, newWriter func(out io.Writer) resultWriter) resultWriter {
+	return func(results <-chan crawler.LinkCrawlerResult) ExitCode {
+		buf := new(bytes.Buffer)
+		code := newWriter(buf)(results)
+
+		if _, err := io.Copy(out, buf); err != nil {
+			log.Error(context.Background(), "failed to write report", "error", err)
+
+			return CodeErrOutput
+		}
+
+		return code
+	}
 }
 
 // bufferedJSONResultWriter creates a new result writer that writes the crawled results to memory and then the output at the end of the process.
 //
+// In the link checking mode, the output is an object of the pages and the checked links instead of an array of the pages. And the process will stop with
+// exit code CodeErrBrokenLinks if there is a broken link.
+//
 // In case of error while writing to the output, the error will be logged and the process will stop with exit code CodeErrOutput.
-func bufferedJSONResultWriter(out io.Writer, pretty bool, log ctxd.Logger) resultWriter {
+func bufferedJSONResultWriter(out io.Writer, cfg outputConfig, log ctxd.Logger) resultWriter {
 	return func(results <-chan crawler.LinkCrawlerResult) (code ExitCode) {
 		code = CodeOK
 		ctx := context.Background()
-		buf := make([]crawlerResult, 0)
+		report := linkCheckReport{Pages: make([]crawlerResult, 0), Links: make([]linkResult, 0)}
 
 		defer func() {
 			enc := json.NewEncoder(out)
 
-			if pretty {
+			if cfg.pretty {
 				enc.SetIndent("", jsonIndent)
 			}
 
-			if err := enc.Encode(buf); err != nil {
+			var v any = report.Pages
+
+			if cfg.checkLinks {
+				v = report
+			}
+
+			if err := enc.Encode(v); err != nil {
 				code = CodeErrOutput
 
 				log.Error(ctx, "failed to encode report", "error", err)
+
+				return
+			}
+
+			if hasBrokenLinks(report.Links) {
+				code = CodeErrBrokenLinks
 			}
 		}()
 
 		for r := range results {
 			log.Debug(ctx, "received result", "result", r)
 
-			buf = append(buf, toCrawlerResult(r))
+			if r.Check != nil {
+				report.Links = append(report.Links, toLinkResult(r))
+
+				continue
+			}
+
+			report.Pages = append(report.Pages, toCrawlerResult(r, cfg.includeLinks))
 		}
 
 		return code
@@ -62,8 +194,13 @@ func bufferedJSONResultWriter(out io.Writer, pretty bool, log ctxd.Logger) resul
 
 // unbufferedJSONResultWriter creates a new result writer that writes the crawled results to output.
 //
+// In the link checking mode, the output is an object of the pages and the checked links instead of an array of the pages. The pages are written as soon as
+// they are ready, and the checked links are written at the end. And the process will stop with exit code CodeErrBrokenLinks if there is a broken link.
+//
 // In case of error while writing to the output, the error will be printed to the error output and the process will stop with exit code CodeErrOutput.
-func unbufferedJSONResultWriter(out, outErr io.Writer, pretty bool) resultWriter {
+//
+// nolint: cyclop
+func unbufferedJSONResultWriter(out, outErr io.Writer, cfg outputConfig) resultWriter {
 	return func(results <-chan crawler.LinkCrawlerResult) (code ExitCode) {
 		writeErr := func(format string, args ...interface{}) {
 			code = CodeErrOutput
@@ -74,16 +211,28 @@ func unbufferedJSONResultWriter(out, outErr io.Writer, pretty bool) resultWriter
 		enc := json.NewEncoder(buf)
 		join := ""
 
-		newL, startIndent, joinTmpl := "", "", ","
+		newL, indent, space := "", "", ""
+
+		if cfg.pretty {
+			newL, indent, space = "\n", jsonIndent, " "
+		}
 
-		if pretty {
-			newL, startIndent = "\n", jsonIndent
-			joinTmpl = ",\n" + startIndent
+		// The pages are nested in the "pages" field in the link checking mode.
+		open, startIndent := "[", indent
 
-			enc.SetIndent(jsonIndent, jsonIndent)
+		if cfg.checkLinks {
+			open, startIndent = "{"+newL+indent+`"pages":`+space+"[", indent+indent
 		}
 
-		if _, err := fmt.Fprint(out, "[", newL, startIndent); err != nil {
+		joinTmpl := "," + newL + startIndent
+
+		if cfg.pretty {
+			enc.SetIndent(startIndent, jsonIndent)
+		}
+
+		links := make([]linkResult, 0)
+
+		if _, err := fmt.Fprint(out, open, newL, startIndent); err != nil {
 			writeErr("could not write [ to output: %s\n", err)
 
 			return
@@ -94,15 +243,44 @@ func unbufferedJSONResultWriter(out, outErr io.Writer, pretty bool) resultWriter
 				return
 			}
 
-			if _, err := fmt.Fprint(out, newL, "]\n"); err != nil {
-				writeErr("could not write ] to output: %s\n", err)
+			if !cfg.checkLinks {
+				if _, err := fmt.Fprint(out, newL, "]\n"); err != nil {
+					writeErr("could not write ] to output: %s\n", err)
+				}
+
+				return
+			}
+
+			buf.Reset()
+			enc.SetIndent(indent, indent)
+
+			if err := enc.Encode(links); err != nil { // This should not happen.
+				writeErr("could not encode links report: %s", err.Error())
+
+				return
+			}
+
+			if _, err := fmt.Fprint(out, newL, indent, "],", newL, indent, `"links":`, space, strings.Trim(buf.String(), "\r\n"), newL, "}\n"); err != nil {
+				writeErr("could not write links report: %s", err.Error())
+
+				return
+			}
+
+			if hasBrokenLinks(links) {
+				code = CodeErrBrokenLinks
 			}
 		}()
 
 		for result := range results {
+			if result.Check != nil {
+				links = append(links, toLinkResult(result))
+
+				continue
+			}
+
 			buf.Reset()
 
-			if err := enc.Encode(toCrawlerResult(result)); err != nil { // This should not happen.
+			if err := enc.Encode(toCrawlerResult(result, cfg.includeLinks)); err != nil { // This should not happen.
 				writeErr("could not encode %q report: %s", result.Source, err.Error())
 
 				return
@@ -121,13 +299,39 @@ func unbufferedJSONResultWriter(out, outErr io.Writer, pretty bool) resultWriter
 	}
 }
 
-// toCrawlerResult converts a crawler.LinkCrawlerResult to crawlerResult for output.
-func toCrawlerResult(r crawler.LinkCrawlerResult) crawlerResult {
+// toCrawlerResult converts a crawler.LinkCrawlerResult to crawlerResult for output. The link lists are only included if includeLinks is true.
+func toCrawlerResult(r crawler.LinkCrawlerResult, includeLinks bool) crawlerResult {
 	result := crawlerResult{
 		PageURL:          r.Source,
 		NumInternalLinks: len(r.InternalLinks),
 		NumExternalLinks: len(r.ExternalLinks),
 		Success:          r.Error == nil,
+		Attempts:         r.Attempts,
+		ParentURL:        r.Parent,
+		Depth:            r.Depth,
+		CanonicalURL:     r.Canonical,
+		NoFollow:         r.NoFollow,
+		Truncated:        r.Truncated,
+		InternalLinksRel: toRelCounts(r.InternalRelCounts()),
+		ExternalLinksRel: toRelCounts(r.ExternalRelCounts()),
+	}
+
+	if r.Index >= 0 {
+		index := r.Index
+		result.Index = &index
+	}
+
+	for _, hop := range r.Redirects {
+		result.Redirects = append(result.Redirects, redirect{
+			URL:        hop.URL,
+			StatusCode: hop.StatusCode,
+			Location:   hop.Location,
+		})
+	}
+
+	if includeLinks {
+		result.InternalLinks = toLinks(r.InternalLinks)
+		result.ExternalLinks = toLinks(r.ExternalLinks)
 	}
 
 	if r.Error != nil {
@@ -137,3 +341,73 @@ func toCrawlerResult(r crawler.LinkCrawlerResult) crawlerResult {
 
 	return result
 }
+
+// toLinks converts the collected links for output.
+func toLinks(collected []collector.Link) []link {
+	links := make([]link, 0, len(collected))
+
+	for _, l := range collected {
+		links = append(links, link{
+			URL:       l.URL,
+			Tag:       l.Tag,
+			Type:      string(l.Type),
+			Rel:       l.Rel,
+			Text:      l.Text,
+			Title:     l.Title,
+			AriaLabel: l.AriaLabel,
+		})
+	}
+
+	return links
+}
+
+// toRelCounts converts the rel counts for output. It returns nil if all the links are followed.
+func toRelCounts(c crawler.RelCounts) *relCounts {
+	if c.NoFollow == 0 && c.Sponsored == 0 && c.UGC == 0 {
+		return nil
+	}
+
+	return &relCounts{
+		Followed:  c.Followed,
+		NoFollow:  c.NoFollow,
+		Sponsored: c.Sponsored,
+		UGC:       c.UGC,
+	}
+}
+
+// toLinkResult converts a crawler.LinkCrawlerResult of a checked link to linkResult for output. The referrers are only kept for the broken links.
+func toLinkResult(r crawler.LinkCrawlerResult) linkResult {
+	result := linkResult{
+		URL:        r.Source,
+		StatusCode: r.Check.StatusCode,
+		LatencyMs:  r.Check.Latency.Milliseconds(),
+		Success:    !isBrokenLink(r),
+	}
+
+	if r.Error != nil {
+		err := r.Error.Error()
+		result.Error = &err
+	}
+
+	if !result.Success {
+		result.Referrers = r.Check.Referrers
+	}
+
+	return result
+}
+
+// isBrokenLink checks whether a checked link is broken. A link is broken if it could not be requested, or its status code is 4xx or 5xx.
+func isBrokenLink(r crawler.LinkCrawlerResult) bool {
+	return r.Error != nil || r.Check.StatusCode >= http.StatusBadRequest
+}
+
+// hasBrokenLinks checks whether there is a broken link in the report.
+func hasBrokenLinks(links []linkResult) bool {
+	for _, l := range links {
+		if !l.Success {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+// csvColumns are the supported columns of the csv and tsv formats.
+var csvColumns = map[string]func(r crawlerResult) string{
+	"page_url": func(r crawlerResult) string { return r.PageURL },
+	"index": func(r crawlerResult) string {
+		if r.Index == nil {
+			return ""
+		}
+
+		return strconv.Itoa(*r.Index)
+	},
+	"internal_links_num": func(r crawlerResult) string { return strconv.Itoa(r.NumInternalLinks) },
+	"external_links_num": func(r crawlerResult) string { return strconv.Itoa(r.NumExternalLinks) },
+	"success":            func(r crawlerResult) string { return strconv.FormatBool(r.Success) },
+	"error": func(r crawlerResult) string {
+		if r.Error == nil {
+			return ""
+		}
+
+		return *r.Error
+	},
+	"attempts":       func(r crawlerResult) string { return strconv.Itoa(r.Attempts) },
+	"parent_url":     func(r crawlerResult) string { return r.ParentURL },
+	"depth":          func(r crawlerResult) string { return strconv.Itoa(r.Depth) },
+	"canonical_url":  func(r crawlerResult) string { return r.CanonicalURL },
+	"truncated":      func(r crawlerResult) string { return strconv.FormatBool(r.Truncated) },
+	"internal_links": func(r crawlerResult) string { return joinLinks(r.InternalLinks) },
+	"external_links": func(r crawlerResult) string { return joinLinks(r.ExternalLinks) },
+}
+
+// defaultCSVColumns are the columns of the csv and tsv formats when there is no column selection.
+var defaultCSVColumns = []string{"page_url", "index", "internal_links_num", "external_links_num", "success", "error", "attempts", "parent_url", "depth", "canonical_url"}
+
+// csvResultWriter creates a new result writer that writes the crawled results to output as comma separated values, one row per result. The tsv format uses
+// the same writer with a tab as the separator.
+//
+// The columns are written in the given order, and the header row is written first if header is true. The link lists are separated by spaces.
+//
+// In case of error while writing to the output, the error will be printed to the error output and the process will stop with exit code CodeErrOutput.
+//
+// See https://www.rfc-editor.org/rfc/rfc4180.
+func csvResultWriter(out, outErr io.Writer, comma rune, columns []string, header bool) resultWriter {
+	return func(results <-chan crawler.LinkCrawlerResult) ExitCode {
+		w := csv.NewWriter(out)
+		w.Comma = comma
+
+		write := func(record []string) error {
+			if err := w.Write(record); err != nil {
+				return err // nolint: wrapcheck // The error is printed out as is.
+			}
+
+			w.Flush()
+
+			return w.Error() // nolint: wrapcheck // The error is printed out as is.
+		}
+
+		if header {
+			if err := write(columns); err != nil {
+				_, _ = fmt.Fprintf(outErr, "could not write header to output: %s\n", err)
+
+				return CodeErrOutput
+			}
+		}
+
+		record := make([]string, len(columns))
+
+		for result := range results {
+			r := toCrawlerResult(result, true)
+
+			for i, col := range columns {
+				record[i] = csvColumns[col](r)
+			}
+
+			if err := write(record); err != nil {
+				_, _ = fmt.Fprintf(outErr, "could not write %q report: %s", result.Source, err.Error())
+
+				return CodeErrOutput
+			}
+		}
+
+		return CodeOK
+	}
+}
+
+// parseCSVColumns validates the column selection. If there is no selection, the default columns are used, and the link lists are added when they are
+// included in the output.
+//
+// nolint: goerr113 // Error will be printed out.
+func parseCSVColumns(columns []string, includeLinks bool) ([]string, error) {
+	if len(columns) == 0 {
+		columns = append([]string(nil), defaultCSVColumns...)
+
+		if includeLinks {
+			columns = append(columns, "internal_links", "external_links")
+		}
+
+		return columns, nil
+	}
+
+	for _, col := range columns {
+		if _, ok := csvColumns[col]; !ok {
+			return nil, fmt.Errorf(`unknown column: %s`, col)
+		}
+	}
+
+	return columns, nil
+}
+
+// joinLinks joins the urls of the links with spaces.
+func joinLinks(links []link) string {
+	urls := make([]string, 0, len(links))
+
+	for _, l := range links {
+		urls = append(urls, l.URL)
+	}
+
+	return strings.Join(urls, " ")
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+// ndjsonResultWriter creates a new result writer that writes the crawled results to output as newline delimited JSON, one object per line, without a
+// wrapping array.
+//
+// In the link checking mode, the checked links are written after the pages, one object per line. And the process will stop with exit code
+// CodeErrBrokenLinks if there is a broken link.
+//
+// In case of error while writing to the output, the error will be printed to the error output and the process will stop with exit code CodeErrOutput.
+//
+// See https://github.com/ndjson/ndjson-spec.
+func ndjsonResultWriter(out, outErr io.Writer, cfg outputConfig) resultWriter {
+	return func(results <-chan crawler.LinkCrawlerResult) (code ExitCode) {
+		code = CodeOK
+		enc := json.NewEncoder(out)
+		brokenLinks := false
+
+		for result := range results {
+			var v any = toCrawlerResult(result, cfg.includeLinks)
+
+			if result.Check != nil {
+				l := toLinkResult(result)
+				brokenLinks = brokenLinks || !l.Success
+				v = l
+			}
+
+			if err := enc.Encode(v); err != nil {
+				_, _ = fmt.Fprintf(outErr, "could not write %q report: %s", result.Source, err.Error())
+
+				return CodeErrOutput
+			}
+		}
+
+		if brokenLinks {
+			code = CodeErrBrokenLinks
+		}
+
+		return code
+	}
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+// reorderBufferSize is the maximum number of results that are held while waiting for the next input source in the ordered mode.
+const reorderBufferSize = 1024
+
+// orderedResultWriter creates a new result writer that passes the results to the next writer in the order of the input sources.
+//
+// The results are held in a bounded reorder buffer until the results of all the sources before them are passed, so the output is still streamed as soon as
+// the next result in order is ready. Every input source has a result, the skipped sources included, so there is no gap in the indexes. The results that are
+// not from the input, such as the discovered pages in the recursive mode or the checked links, are passed right after the last input source that was
+// received before them.
+//
+// When the buffer is full, the reordering stops waiting for the missing sources and their results are passed as soon as they are ready.
+func orderedResultWriter(next resultWriter, size int) resultWriter {
+	return func(results <-chan crawler.LinkCrawlerResult) ExitCode {
+		ordered := make(chan crawler.LinkCrawlerResult)
+
+		go func() {
+			defer close(ordered)
+
+			buf := newReorderBuffer(size)
+
+			for r := range results {
+				for _, o := range buf.push(r) {
+					ordered <- o
+				}
+			}
+
+			for _, o := range buf.flush() {
+				ordered <- o
+			}
+		}()
+
+		code := next(ordered)
+
+		// The next writer may stop early in case of error, drain the remaining results so that the reordering stops.
+		go func() {
+			for range ordered { // nolint: revive // Drain the channel.
+			}
+		}()
+
+		return code
+	}
+}
+
+// reorderBuffer holds the results until the results of all the sources before them are released.
+type reorderBuffer struct {
+	size int
+
+	// next is the index of the next input source to be released.
+	next int
+	// maxIndex is the maximum index of the input sources that have been received, -1 if there is none.
+	maxIndex int
+	// numPending is the number of results held in the buffer.
+	numPending int
+
+	inputs    map[int]crawler.LinkCrawlerResult
+	followers map[int][]crawler.LinkCrawlerResult
+}
+
+// push puts the result into the buffer and returns the results that are ready to be released, in order.
+func (b *reorderBuffer) push(r crawler.LinkCrawlerResult) []crawler.LinkCrawlerResult {
+	if r.Index < 0 {
+		if b.maxIndex < b.next {
+			return []crawler.LinkCrawlerResult{r}
+		}
+
+		b.followers[b.maxIndex] = append(b.followers[b.maxIndex], r)
+		b.numPending++
+
+		return b.release()
+	}
+
+	if r.Index > b.maxIndex {
+		b.maxIndex = r.Index
+	}
+
+	// The reordering stopped waiting for the source because the buffer was full.
+	if r.Index < b.next {
+		return []crawler.LinkCrawlerResult{r}
+	}
+
+	b.inputs[r.Index] = r
+	b.numPending++
+
+	return b.release()
+}
+
+// release returns the results that are ready to be released. When the buffer is full, the missing sources are not waited for.
+func (b *reorderBuffer) release() []crawler.LinkCrawlerResult {
+	out := b.drain(nil)
+
+	for b.numPending > b.size && len(b.inputs) > 0 {
+		b.next = b.minIndex()
+		out = b.drain(out)
+	}
+
+	return out
+}
+
+// drain appends the results from the next input source until a source is missing.
+func (b *reorderBuffer) drain(out []crawler.LinkCrawlerResult) []crawler.LinkCrawlerResult {
+	for {
+		r, ok := b.inputs[b.next]
+		if !ok {
+			return out
+		}
+
+		out = append(out, r)
+		out = append(out, b.followers[b.next]...)
+
+		b.numPending -= 1 + len(b.followers[b.next])
+
+		delete(b.inputs, b.next)
+		delete(b.followers, b.next)
+
+		b.next++
+	}
+}
+
+// minIndex returns the minimum index of the input sources in the buffer. It is -1 if there is none.
+func (b *reorderBuffer) minIndex() int {
+	idx := -1
+
+	for i := range b.inputs {
+		if idx < 0 || i < idx {
+			idx = i
+		}
+	}
+
+	return idx
+}
+
+// flush returns all the results in the buffer, in order.
+func (b *reorderBuffer) flush() []crawler.LinkCrawlerResult {
+	indexes := make([]int, 0, len(b.inputs))
+
+	for i := range b.inputs {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	out := make([]crawler.LinkCrawlerResult, 0, b.numPending)
+
+	for _, i := range indexes {
+		out = append(out, b.inputs[i])
+		out = append(out, b.followers[i]...)
+	}
+
+	b.inputs = make(map[int]crawler.LinkCrawlerResult)
+	b.followers = make(map[int][]crawler.LinkCrawlerResult)
+	b.numPending = 0
+
+	return out
+}
+
+// newReorderBuffer creates a new reorder buffer that holds at most size results.
+func newReorderBuffer(size int) *reorderBuffer {
+	return &reorderBuffer{
+		size:      size,
+		maxIndex:  -1,
+		inputs:    make(map[int]crawler.LinkCrawlerResult),
+		followers: make(map[int][]crawler.LinkCrawlerResult),
+	}
+}
@@ -0,0 +1,86 @@
+package brotli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// bitReader reads the bits of a stream, starting from the least significant bit of each byte.
+//
+// The first error is kept, and the reads after it return zeros, so that the callers could check the error once after a group of reads.
+type bitReader struct {
+	r     *bufio.Reader
+	bits  uint64
+	nbits uint
+	err   error
+}
+
+// readBits reads n bits, n is at most 32.
+func (br *bitReader) readBits(n uint) uint32 {
+	for br.nbits < n {
+		if br.err != nil {
+			return 0
+		}
+
+		b, err := br.r.ReadByte()
+		if err != nil {
+			br.fail(err)
+
+			return 0
+		}
+
+		br.bits |= uint64(b) << br.nbits
+		br.nbits += 8
+	}
+
+	v := uint32(br.bits & (1<<n - 1))
+	br.bits >>= n
+	br.nbits -= n
+
+	return v
+}
+
+// readBit reads a bit.
+func (br *bitReader) readBit() uint32 {
+	return br.readBits(1)
+}
+
+// alignToByte skips the bits up to the next byte boundary. It returns false if the skipped bits are not zeros, because the padding must be zeros.
+func (br *bitReader) alignToByte() bool {
+	return br.readBits(br.nbits%8) == 0
+}
+
+// readAlignedBytes reads the bytes of an uncompressed data, the reader must be aligned to a byte boundary.
+func (br *bitReader) readAlignedBytes(dst []byte) {
+	for i := range dst {
+		if br.nbits == 0 {
+			if _, err := io.ReadFull(br.r, dst[i:]); err != nil {
+				br.fail(err)
+			}
+
+			return
+		}
+
+		dst[i] = byte(br.readBits(8))
+	}
+}
+
+// fail keeps the first error. The end of the stream is unexpected, because the stream ends only after its last meta-block.
+func (br *bitReader) fail(err error) {
+	if br.err != nil {
+		return
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	br.err = err
+}
+
+// corrupt keeps an ErrCorrupted error with the reason.
+func (br *bitReader) corrupt(reason string) {
+	br.fail(fmt.Errorf("%w: %s", ErrCorrupted, reason))
+}
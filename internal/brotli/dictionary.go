@@ -0,0 +1,259 @@
+package brotli
+
+import (
+	_ "embed"
+)
+
+const (
+	// minWordLength and maxWordLength are the lengths of the shortest and the longest words of the static dictionary.
+	minWordLength = 4
+	maxWordLength = 24
+)
+
+// dictionary is the static dictionary of RFC 7932, the words are grouped by their lengths.
+// See https://www.rfc-editor.org/rfc/rfc7932#appendix-A.
+//
+//go:embed dictionary.bin
+var dictionary []byte
+
+// dictionarySizeBits is the number of bits of the word index, for each word length.
+var dictionarySizeBits = [maxWordLength + 1]uint{
+	0, 0, 0, 0, 10, 10, 11, 11, 10, 10, 10, 10, 10, 9, 9, 8, 7, 7, 8, 7, 7, 6, 6, 5, 5,
+}
+
+// dictionaryOffsets is the offset of the first word, for each word length.
+var dictionaryOffsets = func() [maxWordLength + 2]int {
+	var offsets [maxWordLength + 2]int
+
+	for l := minWordLength; l <= maxWordLength; l++ {
+		offsets[l+1] = offsets[l] + l<<dictionarySizeBits[l]
+	}
+
+	return offsets
+}()
+
+// The types of the transforms.
+// See https://www.rfc-editor.org/rfc/rfc7932#section-8.
+const (
+	transformIdentity = iota
+	transformOmitLast1
+	transformOmitLast2
+	transformOmitLast3
+	transformOmitLast4
+	transformOmitLast5
+	transformOmitLast6
+	transformOmitLast7
+	transformOmitLast8
+	transformOmitLast9
+	transformUppercaseFirst
+	transformUppercaseAll
+	transformOmitFirst1
+	transformOmitFirst2
+	transformOmitFirst3
+	transformOmitFirst4
+	transformOmitFirst5
+	transformOmitFirst6
+	transformOmitFirst7
+	transformOmitFirst8
+	transformOmitFirst9
+)
+
+// transform is a transformation of a dictionary word.
+type transform struct {
+	prefix string
+	kind   uint8
+	suffix string
+}
+
+// appendWord appends the transformed word to dst.
+func (t transform) appendWord(dst, word []byte) []byte {
+	dst = append(dst, t.prefix...)
+
+	switch {
+	case t.kind >= transformOmitLast1 && t.kind <= transformOmitLast9:
+		word = word[:len(word)-omitted(int(t.kind-transformOmitLast1+1), len(word))]
+
+	case t.kind >= transformOmitFirst1:
+		word = word[omitted(int(t.kind-transformOmitFirst1+1), len(word)):]
+	}
+
+	start := len(dst)
+	dst = append(dst, word...)
+
+	switch t.kind {
+	case transformUppercaseFirst:
+		toUpperCase(dst[start:])
+
+	case transformUppercaseAll:
+		for i := start; i < len(dst); {
+			i += toUpperCase(dst[i:])
+		}
+	}
+
+	return append(dst, t.suffix...)
+}
+
+// omitted returns the number of bytes to omit from a word, which is at most the length of the word.
+func omitted(n, length int) int {
+	if n > length {
+		return length
+	}
+
+	return n
+}
+
+// toUpperCase turns the first character of p into upper case, the same way as RFC 7932 does, and returns the length of the character. The character is
+// not always valid UTF-8, so only the bytes within p are changed.
+func toUpperCase(p []byte) int {
+	if len(p) == 0 {
+		return 1
+	}
+
+	switch {
+	case p[0] < 0xc0:
+		if p[0] >= 'a' && p[0] <= 'z' {
+			p[0] ^= 32
+		}
+
+		return 1
+
+	case p[0] < 0xe0:
+		if len(p) > 1 {
+			p[1] ^= 32
+		}
+
+		return 2 // nolint: gomnd // The length of a 2-byte character.
+	}
+
+	if len(p) > 2 { // nolint: gomnd // The last byte of a 3-byte character.
+		p[2] ^= 5
+	}
+
+	return 3 // nolint: gomnd // The length of a 3-byte character.
+}
+
+// transforms is the transforms of RFC 7932, the index is the transform id.
+// See https://www.rfc-editor.org/rfc/rfc7932#appendix-B.
+var transforms = [...]transform{
+	{"", transformIdentity, ""},
+	{"", transformIdentity, " "},
+	{" ", transformIdentity, " "},
+	{"", transformOmitFirst1, ""},
+	{"", transformUppercaseFirst, " "},
+	{"", transformIdentity, " the "},
+	{" ", transformIdentity, ""},
+	{"s ", transformIdentity, " "},
+	{"", transformIdentity, " of "},
+	{"", transformUppercaseFirst, ""},
+	{"", transformIdentity, " and "},
+	{"", transformOmitFirst2, ""},
+	{"", transformOmitLast1, ""},
+	{", ", transformIdentity, " "},
+	{"", transformIdentity, ", "},
+	{" ", transformUppercaseFirst, " "},
+	{"", transformIdentity, " in "},
+	{"", transformIdentity, " to "},
+	{"e ", transformIdentity, " "},
+	{"", transformIdentity, "\""},
+	{"", transformIdentity, "."},
+	{"", transformIdentity, "\">"},
+	{"", transformIdentity, "\n"},
+	{"", transformOmitLast3, ""},
+	{"", transformIdentity, "]"},
+	{"", transformIdentity, " for "},
+	{"", transformOmitFirst3, ""},
+	{"", transformOmitLast2, ""},
+	{"", transformIdentity, " a "},
+	{"", transformIdentity, " that "},
+	{" ", transformUppercaseFirst, ""},
+	{"", transformIdentity, ". "},
+	{".", transformIdentity, ""},
+	{" ", transformIdentity, ", "},
+	{"", transformOmitFirst4, ""},
+	{"", transformIdentity, " with "},
+	{"", transformIdentity, "'"},
+	{"", transformIdentity, " from "},
+	{"", transformIdentity, " by "},
+	{"", transformOmitFirst5, ""},
+	{"", transformOmitFirst6, ""},
+	{" the ", transformIdentity, ""},
+	{"", transformOmitLast4, ""},
+	{"", transformIdentity, ". The "},
+	{"", transformUppercaseAll, ""},
+	{"", transformIdentity, " on "},
+	{"", transformIdentity, " as "},
+	{"", transformIdentity, " is "},
+	{"", transformOmitLast7, ""},
+	{"", transformOmitLast1, "ing "},
+	{"", transformIdentity, "\n\t"},
+	{"", transformIdentity, ":"},
+	{" ", transformIdentity, ". "},
+	{"", transformIdentity, "ed "},
+	{"", transformOmitFirst9, ""},
+	{"", transformOmitFirst7, ""},
+	{"", transformOmitLast6, ""},
+	{"", transformIdentity, "("},
+	{"", transformUppercaseFirst, ", "},
+	{"", transformOmitLast8, ""},
+	{"", transformIdentity, " at "},
+	{"", transformIdentity, "ly "},
+	{" the ", transformIdentity, " of "},
+	{"", transformOmitLast5, ""},
+	{"", transformOmitLast9, ""},
+	{" ", transformUppercaseFirst, ", "},
+	{"", transformUppercaseFirst, "\""},
+	{".", transformIdentity, "("},
+	{"", transformUppercaseAll, " "},
+	{"", transformUppercaseFirst, "\">"},
+	{"", transformIdentity, "=\""},
+	{" ", transformIdentity, "."},
+	{".com/", transformIdentity, ""},
+	{" the ", transformIdentity, " of the "},
+	{"", transformUppercaseFirst, "'"},
+	{"", transformIdentity, ". This "},
+	{"", transformIdentity, ","},
+	{".", transformIdentity, " "},
+	{"", transformUppercaseFirst, "("},
+	{"", transformUppercaseFirst, "."},
+	{"", transformIdentity, " not "},
+	{" ", transformIdentity, "=\""},
+	{"", transformIdentity, "er "},
+	{" ", transformUppercaseAll, " "},
+	{"", transformIdentity, "al "},
+	{" ", transformUppercaseAll, ""},
+	{"", transformIdentity, "='"},
+	{"", transformUppercaseAll, "\""},
+	{"", transformUppercaseFirst, ". "},
+	{" ", transformIdentity, "("},
+	{"", transformIdentity, "ful "},
+	{" ", transformUppercaseFirst, ". "},
+	{"", transformIdentity, "ive "},
+	{"", transformIdentity, "less "},
+	{"", transformUppercaseAll, "'"},
+	{"", transformIdentity, "est "},
+	{" ", transformUppercaseFirst, "."},
+	{"", transformUppercaseAll, "\">"},
+	{" ", transformIdentity, "='"},
+	{"", transformUppercaseFirst, ","},
+	{"", transformIdentity, "ize "},
+	{"", transformUppercaseAll, "."},
+	{"\xc2\xa0", transformIdentity, ""},
+	{" ", transformIdentity, ","},
+	{"", transformUppercaseFirst, "=\""},
+	{"", transformUppercaseAll, "=\""},
+	{"", transformIdentity, "ous "},
+	{"", transformUppercaseAll, ", "},
+	{"", transformUppercaseFirst, "='"},
+	{" ", transformUppercaseFirst, ","},
+	{" ", transformUppercaseAll, "=\""},
+	{" ", transformUppercaseAll, ", "},
+	{"", transformUppercaseAll, ","},
+	{"", transformUppercaseAll, "("},
+	{"", transformUppercaseAll, ". "},
+	{" ", transformUppercaseAll, "."},
+	{"", transformUppercaseAll, "='"},
+	{" ", transformUppercaseAll, ". "},
+	{" ", transformUppercaseFirst, "=\""},
+	{" ", transformUppercaseAll, "='"},
+	{" ", transformUppercaseFirst, "='"},
+}
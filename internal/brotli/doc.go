@@ -0,0 +1,4 @@
+// Package brotli provides a decoder for the brotli compressed data format.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932.
+package brotli
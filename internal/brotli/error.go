@@ -0,0 +1,11 @@
+package brotli
+
+var _ error = (*Error)(nil)
+
+// Error is a brotli error.
+type Error string
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
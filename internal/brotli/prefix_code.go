@@ -0,0 +1,228 @@
+package brotli
+
+import (
+	"math/bits"
+)
+
+const (
+	// maxCodeLength is the maximum length of a prefix code.
+	maxCodeLength = 15
+	// numCodeLengthCodes is the size of the alphabet of the code lengths.
+	numCodeLengthCodes = 18
+	// repeatPreviousCodeLength and repeatZeroCodeLength are the code length symbols that repeat the previous non-zero code length and zero.
+	repeatPreviousCodeLength = 16
+	repeatZeroCodeLength     = 17
+)
+
+// codeLengthCodeOrder is the order of the code lengths of the code length alphabet.
+var codeLengthCodeOrder = [numCodeLengthCodes]uint8{1, 2, 3, 4, 0, 5, 17, 6, 16, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// codeLengthCodeLengths is the static prefix code for reading the code lengths of the code length alphabet.
+// See https://www.rfc-editor.org/rfc/rfc7932#section-3.5.
+var codeLengthCodeLengths = newPrefixCode([]uint8{2, 4, 3, 2, 2, 4})
+
+// prefixCode is a canonical prefix code of an alphabet.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-3.2.
+type prefixCode struct {
+	// counts is the number of codes of each length.
+	counts [maxCodeLength + 1]uint16
+	// symbols is the symbols ordered by their codes.
+	symbols []uint16
+}
+
+// newPrefixCode creates a new prefix code from the code lengths of the symbols, zero means the symbol is not used.
+func newPrefixCode(lengths []uint8) *prefixCode {
+	c := &prefixCode{}
+
+	for _, l := range lengths {
+		c.counts[l]++
+	}
+
+	c.counts[0] = 0
+
+	var offsets [maxCodeLength + 2]int
+
+	for l := 1; l <= maxCodeLength; l++ {
+		offsets[l+1] = offsets[l] + int(c.counts[l])
+	}
+
+	c.symbols = make([]uint16, offsets[maxCodeLength+1])
+
+	for s, l := range lengths {
+		if l != 0 {
+			c.symbols[offsets[l]] = uint16(s)
+			offsets[l]++
+		}
+	}
+
+	return c
+}
+
+// readSymbol reads a symbol of the prefix code. The code of an alphabet that has only one symbol is zero bits.
+func (br *bitReader) readSymbol(c *prefixCode) int {
+	if len(c.symbols) == 1 {
+		return int(c.symbols[0])
+	}
+
+	// The codes are packed from their most significant bits.
+	code, first, index := 0, 0, 0
+
+	for l := 1; l <= maxCodeLength; l++ {
+		code |= int(br.readBit())
+		count := int(c.counts[l])
+
+		if code-first < count {
+			return int(c.symbols[index+code-first])
+		}
+
+		index += count
+		first = (first + count) << 1
+		code <<= 1
+	}
+
+	br.corrupt("invalid prefix code")
+
+	return 0
+}
+
+// readPrefixCode reads a prefix code of an alphabet, it is either a simple or a complex prefix code.
+func (br *bitReader) readPrefixCode(alphabetSize int) *prefixCode {
+	lengths := make([]uint8, alphabetSize)
+
+	if hskip := br.readBits(2); hskip == 1 {
+		br.readSimpleCodeLengths(lengths)
+	} else {
+		br.readComplexCodeLengths(lengths, hskip)
+	}
+
+	return newPrefixCode(lengths)
+}
+
+// readSimpleCodeLengths reads the code lengths of a simple prefix code, which has 1 to 4 symbols.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-3.4.
+func (br *bitReader) readSimpleCodeLengths(lengths []uint8) {
+	numSymbols := int(br.readBits(2)) + 1
+	symbolBits := uint(bits.Len(uint(len(lengths) - 1)))
+	symbols := make([]int, numSymbols)
+
+	for i := range symbols {
+		symbols[i] = int(br.readBits(symbolBits))
+
+		if symbols[i] >= len(lengths) {
+			br.corrupt("invalid symbol of simple prefix code")
+
+			return
+		}
+
+		for j := 0; j < i; j++ {
+			if symbols[j] == symbols[i] {
+				br.corrupt("duplicate symbol of simple prefix code")
+
+				return
+			}
+		}
+	}
+
+	// The codes of the same length are assigned in the order of the symbols, so only the lengths matter.
+	codeLengths := [][]uint8{{1}, {1, 1}, {1, 2, 2}, {2, 2, 2, 2}}[numSymbols-1]
+
+	if numSymbols == 4 && br.readBit() == 1 {
+		codeLengths = []uint8{1, 2, 3, 3}
+	}
+
+	for i, s := range symbols {
+		lengths[s] = codeLengths[i]
+	}
+}
+
+// readComplexCodeLengths reads the code lengths of a complex prefix code. The code lengths are prefix coded with the code length alphabet, and the first
+// hskip code lengths of the code length alphabet are skipped.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-3.5.
+func (br *bitReader) readComplexCodeLengths(lengths []uint8, hskip uint32) {
+	var codeLengthLengths [numCodeLengthCodes]uint8
+
+	space, numCodes := 32, 0
+
+	for i := int(hskip); i < numCodeLengthCodes && space > 0; i++ {
+		l := uint8(br.readSymbol(codeLengthCodeLengths))
+		codeLengthLengths[codeLengthCodeOrder[i]] = l
+
+		if l != 0 {
+			space -= 32 >> l
+			numCodes++
+		}
+	}
+
+	if numCodes != 1 && space != 0 {
+		br.corrupt("invalid code length code lengths")
+
+		return
+	}
+
+	codeLengthCode := newPrefixCode(codeLengthLengths[:])
+	prevLength, repeat, repeatLength := uint8(8), 0, uint8(0)
+	space = 1 << maxCodeLength
+
+	for s := 0; s < len(lengths) && space > 0; {
+		if br.err != nil {
+			return
+		}
+
+		l := br.readSymbol(codeLengthCode)
+
+		if l < repeatPreviousCodeLength {
+			repeat = 0
+			lengths[s] = uint8(l)
+			s++
+
+			if l != 0 {
+				prevLength = uint8(l)
+				space -= 1 << maxCodeLength >> l
+			}
+
+			continue
+		}
+
+		// A repeat code that follows another one of the same kind extends its count.
+		extraBits, newLength := uint(2), prevLength
+		if l == repeatZeroCodeLength {
+			extraBits, newLength = 3, 0
+		}
+
+		if repeatLength != newLength {
+			repeat, repeatLength = 0, newLength
+		}
+
+		oldRepeat := repeat
+
+		if repeat > 0 {
+			repeat = (repeat - 2) << extraBits
+		}
+
+		repeat += int(br.readBits(extraBits)) + 3
+		delta := repeat - oldRepeat
+
+		if s+delta > len(lengths) {
+			br.corrupt("too many code lengths")
+
+			return
+		}
+
+		for i := 0; i < delta; i++ {
+			lengths[s+i] = repeatLength
+		}
+
+		s += delta
+
+		if repeatLength != 0 {
+			space -= delta << (maxCodeLength - repeatLength)
+		}
+	}
+
+	if space != 0 {
+		br.corrupt("invalid code lengths")
+	}
+}
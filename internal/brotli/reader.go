@@ -0,0 +1,677 @@
+package brotli
+
+import (
+	"bufio"
+	"io"
+)
+
+const (
+	// ErrCorrupted indicates that the stream is not a valid brotli stream.
+	ErrCorrupted = Error("corrupted brotli stream")
+)
+
+const (
+	// numLiteralCodes, numCommandCodes and numBlockCountCodes are the sizes of the alphabets of the literals, the insert-and-copy commands and the block
+	// counts.
+	numLiteralCodes    = 256
+	numCommandCodes    = 704
+	numBlockCountCodes = 26
+	// numDistanceShortCodes is the number of distance codes that refer to the last distances.
+	numDistanceShortCodes = 16
+	// maxBlockCount is the block count of a category that has only one block type, it covers the whole meta-block.
+	maxBlockCount = 1 << 24
+	// literalContextBits and distanceContextBits are the number of bits of the context ids of the literals and the distances.
+	literalContextBits  = 6
+	distanceContextBits = 2
+)
+
+// The insert lengths, the copy lengths and the block counts are a base value and a number of extra bits.
+// See https://www.rfc-editor.org/rfc/rfc7932#section-5 and https://www.rfc-editor.org/rfc/rfc7932#section-6.
+var (
+	insertLengthBase  = [24]int{0, 1, 2, 3, 4, 5, 6, 8, 10, 14, 18, 26, 34, 50, 66, 98, 130, 194, 322, 578, 1090, 2114, 6210, 22594}
+	insertLengthExtra = [24]uint{0, 0, 0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 12, 14, 24}
+	copyLengthBase    = [24]int{2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 14, 18, 22, 30, 38, 54, 70, 102, 134, 198, 326, 582, 1094, 2118}
+	copyLengthExtra   = [24]uint{0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 24}
+	blockCountBase    = [numBlockCountCodes]int{
+		1, 5, 9, 13, 17, 25, 33, 41, 49, 65, 81, 97, 113, 145, 177, 209, 241, 305, 369, 497, 753, 1265, 2289, 4337, 8433, 16625,
+	}
+	blockCountExtra = [numBlockCountCodes]uint{2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 6, 6, 7, 8, 9, 10, 11, 12, 13, 24}
+
+	// commandInsertBase and commandCopyBase are the first insert and copy length codes of each 64-code cell of the insert-and-copy alphabet. The commands of
+	// the first two cells use the last distance, without a distance code.
+	commandInsertBase = [11]int{0, 0, 0, 0, 8, 8, 0, 16, 8, 16, 16}
+	commandCopyBase   = [11]int{0, 8, 0, 8, 0, 8, 16, 0, 16, 8, 16}
+
+	// distanceShortCodeIndex and distanceShortCodeOffset are the last distance that a short distance code refers to, 0 is the last one, and the offset to
+	// add to it.
+	distanceShortCodeIndex  = [numDistanceShortCodes]int{0, 1, 2, 3, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1}
+	distanceShortCodeOffset = [numDistanceShortCodes]int{0, 0, 0, 0, -1, 1, -2, 2, -3, 3, -1, 1, -2, 2, -3, 3}
+)
+
+var _ io.Reader = (*Reader)(nil)
+
+// Reader is a reader that decompresses a brotli stream.
+//
+//	r := brotli.NewReader(resp.Body)
+//	b, err := io.ReadAll(r)
+type Reader struct {
+	br  bitReader
+	err error
+
+	// started is true when the stream header has been read.
+	started bool
+	// window is the maximum distance of the back references.
+	window int
+	// out is the decoded data. It keeps the last window bytes for the back references, followed by the bytes that have not been read.
+	out []byte
+	// off is the index of the first byte of out that has not been read.
+	off int
+	// pos is the number of decoded bytes.
+	pos int
+	// dist is the ring of the last four distances, dist[distIdx&3] is the last one.
+	dist    [4]int
+	distIdx int
+
+	// last is true when the current meta-block is the last one.
+	last bool
+	// remaining is the number of bytes to decode in the current compressed meta-block.
+	remaining int
+
+	literals, commands, distances blockSwitch
+	contextModes                  []uint8
+	literalMap, distanceMap       []uint8
+	literalCodes                  []*prefixCode
+	commandCodes                  []*prefixCode
+	distanceCodes                 []*prefixCode
+	postfixBits                   uint
+	directCodes                   int
+}
+
+// blockSwitch is the block types of a category in a meta-block: the literals, the commands, or the distances.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-6.
+type blockSwitch struct {
+	num       int
+	typeCode  *prefixCode
+	countCode *prefixCode
+	blockType int
+	prevType  int
+	// remaining is the number of symbols left in the current block.
+	remaining int
+}
+
+// Read reads the decompressed data.
+func (r *Reader) Read(p []byte) (int, error) {
+	for r.off == len(r.out) {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		r.err = r.decode()
+	}
+
+	n := copy(p, r.out[r.off:])
+	r.off += n
+
+	return n, nil
+}
+
+// decode decodes the next command, or the next meta-block header. It returns io.EOF after the last meta-block.
+func (r *Reader) decode() error {
+	r.compact()
+
+	if !r.started {
+		r.started = true
+		r.readStreamHeader()
+
+		return r.br.err
+	}
+
+	if r.remaining > 0 {
+		r.decodeCommand()
+
+		return r.br.err
+	}
+
+	if r.last {
+		return io.EOF
+	}
+
+	r.readMetaBlockHeader()
+
+	return r.br.err
+}
+
+// compact drops the bytes that have been read and are out of the window, so that the decoded data does not grow with the stream.
+func (r *Reader) compact() {
+	drop := len(r.out) - r.window
+	if r.off < drop {
+		drop = r.off
+	}
+
+	// Move the window only when it is worth it.
+	if drop < r.window || drop < 1<<16 {
+		return
+	}
+
+	n := copy(r.out, r.out[drop:])
+	r.out = r.out[:n]
+	r.off -= drop
+}
+
+// readStreamHeader reads the size of the sliding window.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-9.1.
+func (r *Reader) readStreamHeader() {
+	br := &r.br
+	wbits := uint(16)
+
+	if br.readBit() == 1 {
+		if n := br.readBits(3); n != 0 {
+			wbits = 17 + uint(n)
+		} else {
+			switch n := br.readBits(3); n {
+			case 0:
+				wbits = 17
+
+			case 1:
+				br.corrupt("large window is not supported")
+
+			default:
+				wbits = 8 + uint(n)
+			}
+		}
+	}
+
+	r.window = 1<<wbits - 16
+	r.dist = [4]int{16, 15, 11, 4}
+	r.distIdx = 3
+}
+
+// readMetaBlockHeader reads the header of a meta-block. The metadata and the uncompressed data are read at once, and the compressed data is left to
+// decodeCommand.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-9.2.
+func (r *Reader) readMetaBlockHeader() {
+	br := &r.br
+
+	r.last = br.readBit() == 1
+	if r.last && br.readBit() == 1 {
+		// The last meta-block is empty.
+		return
+	}
+
+	numNibbles := int(br.readBits(2)) + 4
+	if numNibbles == 7 { // nolint: gomnd // The metadata has no nibble.
+		r.skipMetadata()
+
+		return
+	}
+
+	length := 0
+
+	for i := 0; i < numNibbles; i++ {
+		nibble := int(br.readBits(4))
+
+		if i > 3 && i == numNibbles-1 && nibble == 0 {
+			br.corrupt("invalid meta-block length")
+
+			return
+		}
+
+		length |= nibble << (4 * i)
+	}
+
+	length++
+
+	if !r.last && br.readBit() == 1 {
+		r.readUncompressed(length)
+
+		return
+	}
+
+	r.remaining = length
+	r.readCompressedHeader()
+}
+
+// skipMetadata skips the metadata of a meta-block.
+func (r *Reader) skipMetadata() {
+	br := &r.br
+
+	if br.readBit() != 0 {
+		br.corrupt("invalid reserved bit")
+
+		return
+	}
+
+	numBytes := int(br.readBits(2))
+	length := 0
+
+	for i := 0; i < numBytes; i++ {
+		b := int(br.readBits(8))
+
+		if i > 0 && i == numBytes-1 && b == 0 {
+			br.corrupt("invalid metadata length")
+
+			return
+		}
+
+		length |= b << (8 * i)
+	}
+
+	if numBytes > 0 {
+		length++
+	}
+
+	if !br.alignToByte() {
+		br.corrupt("invalid padding")
+
+		return
+	}
+
+	br.readAlignedBytes(make([]byte, length))
+}
+
+// readUncompressed reads the data of an uncompressed meta-block.
+func (r *Reader) readUncompressed(length int) {
+	br := &r.br
+
+	if !br.alignToByte() {
+		br.corrupt("invalid padding")
+
+		return
+	}
+
+	start := len(r.out)
+	r.out = append(r.out, make([]byte, length)...)
+
+	br.readAlignedBytes(r.out[start:])
+
+	r.pos += length
+}
+
+// readCompressedHeader reads the block types, the context maps and the prefix codes of a compressed meta-block.
+func (r *Reader) readCompressedHeader() {
+	br := &r.br
+
+	r.readBlockSwitch(&r.literals)
+	r.readBlockSwitch(&r.commands)
+	r.readBlockSwitch(&r.distances)
+
+	r.postfixBits = uint(br.readBits(2))
+	r.directCodes = int(br.readBits(4)) << r.postfixBits
+
+	r.contextModes = make([]uint8, r.literals.num)
+	for i := range r.contextModes {
+		r.contextModes[i] = uint8(br.readBits(2))
+	}
+
+	var numLiteralTrees, numDistanceTrees int
+
+	r.literalMap, numLiteralTrees = r.readContextMap(r.literals.num << literalContextBits)
+	r.distanceMap, numDistanceTrees = r.readContextMap(r.distances.num << distanceContextBits)
+
+	r.literalCodes = r.readPrefixCodes(numLiteralTrees, numLiteralCodes)
+	r.commandCodes = r.readPrefixCodes(r.commands.num, numCommandCodes)
+	r.distanceCodes = r.readPrefixCodes(numDistanceTrees, numDistanceShortCodes+r.directCodes+48<<r.postfixBits)
+}
+
+// readPrefixCodes reads n prefix codes of an alphabet.
+func (r *Reader) readPrefixCodes(n, alphabetSize int) []*prefixCode {
+	codes := make([]*prefixCode, n)
+
+	for i := range codes {
+		if r.br.err != nil {
+			return nil
+		}
+
+		codes[i] = r.br.readPrefixCode(alphabetSize)
+	}
+
+	return codes
+}
+
+// readBlockSwitch reads the number of block types of a category, and the prefix codes for switching the blocks.
+func (r *Reader) readBlockSwitch(b *blockSwitch) {
+	br := &r.br
+
+	*b = blockSwitch{num: r.readVarLenUint8() + 1, prevType: 1, remaining: maxBlockCount}
+
+	if b.num < 2 { // nolint: gomnd // There is no block switch with only one block type.
+		return
+	}
+
+	b.typeCode = br.readPrefixCode(b.num + 2)
+	b.countCode = br.readPrefixCode(numBlockCountCodes)
+	b.remaining = r.readBlockCount(b.countCode)
+}
+
+// switchBlock reads the type and the count of the next block of a category.
+func (r *Reader) switchBlock(b *blockSwitch) {
+	if b.typeCode == nil {
+		// Corrupted streams may have more symbols than maxBlockCount.
+		r.br.corrupt("invalid block count")
+
+		return
+	}
+
+	blockType := r.br.readSymbol(b.typeCode)
+
+	switch blockType {
+	case 0:
+		blockType = b.prevType
+
+	case 1:
+		blockType = (b.blockType + 1) % b.num
+
+	default:
+		blockType -= 2
+	}
+
+	if blockType >= b.num {
+		r.br.corrupt("invalid block type")
+
+		return
+	}
+
+	b.prevType, b.blockType = b.blockType, blockType
+	b.remaining = r.readBlockCount(b.countCode)
+}
+
+// readBlockCount reads the number of symbols of a block.
+func (r *Reader) readBlockCount(c *prefixCode) int {
+	code := r.br.readSymbol(c)
+
+	return blockCountBase[code] + int(r.br.readBits(blockCountExtra[code]))
+}
+
+// readVarLenUint8 reads a number from 0 to 255 that is encoded with a variable length code.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-9.2.
+func (r *Reader) readVarLenUint8() int {
+	br := &r.br
+
+	if br.readBit() == 0 {
+		return 0
+	}
+
+	n := uint(br.readBits(3))
+	if n == 0 {
+		return 1
+	}
+
+	return 1<<n + int(br.readBits(n))
+}
+
+// readContextMap reads a context map of the given size, and returns the map with the number of prefix codes that it refers to.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-7.3.
+func (r *Reader) readContextMap(size int) ([]uint8, int) {
+	br := &r.br
+	numTrees := r.readVarLenUint8() + 1
+	contextMap := make([]uint8, size)
+
+	if numTrees < 2 { // nolint: gomnd // All the contexts use the only prefix code.
+		return contextMap, numTrees
+	}
+
+	maxRunLengthPrefix := 0
+	if br.readBit() == 1 {
+		maxRunLengthPrefix = int(br.readBits(4)) + 1
+	}
+
+	c := br.readPrefixCode(numTrees + maxRunLengthPrefix)
+
+	for i := 0; i < size; {
+		if br.err != nil {
+			return nil, 0
+		}
+
+		switch s := br.readSymbol(c); {
+		case s == 0:
+			i++
+
+		case s <= maxRunLengthPrefix:
+			// A run of zeros.
+			n := 1<<s + int(br.readBits(uint(s)))
+
+			if i+n > size {
+				br.corrupt("invalid context map")
+
+				return nil, 0
+			}
+
+			i += n
+
+		default:
+			contextMap[i] = uint8(s - maxRunLengthPrefix)
+			i++
+		}
+	}
+
+	if br.readBit() == 1 {
+		inverseMoveToFront(contextMap)
+	}
+
+	for _, v := range contextMap {
+		if int(v) >= numTrees {
+			br.corrupt("invalid context map")
+
+			return nil, 0
+		}
+	}
+
+	return contextMap, numTrees
+}
+
+// inverseMoveToFront applies the inverse move-to-front transform to the values.
+func inverseMoveToFront(values []uint8) {
+	var mtf [256]uint8
+
+	for i := range mtf {
+		mtf[i] = uint8(i)
+	}
+
+	for i, idx := range values {
+		v := mtf[idx]
+		values[i] = v
+
+		copy(mtf[1:int(idx)+1], mtf[:idx])
+		mtf[0] = v
+	}
+}
+
+// decodeCommand decodes an insert-and-copy command: the literals to insert, and the bytes to copy from a back reference or from the static dictionary.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-9.3.
+func (r *Reader) decodeCommand() {
+	br := &r.br
+
+	r.useBlock(&r.commands)
+
+	code := br.readSymbol(r.commandCodes[r.commands.blockType])
+	cell := code >> 6
+	insertCode := commandInsertBase[cell] + (code>>3)&7
+	copyCode := commandCopyBase[cell] + code&7
+	insertLength := insertLengthBase[insertCode] + int(br.readBits(insertLengthExtra[insertCode]))
+	copyLength := copyLengthBase[copyCode] + int(br.readBits(copyLengthExtra[copyCode]))
+
+	if br.err != nil {
+		return
+	}
+
+	if insertLength > r.remaining {
+		br.corrupt("invalid insert length")
+
+		return
+	}
+
+	r.insertLiterals(insertLength)
+
+	if br.err != nil || r.remaining == 0 {
+		// The copy of the last command of a meta-block is ignored.
+		return
+	}
+
+	distance, distanceCode := r.dist[r.distIdx&3], 0
+
+	if cell > 1 {
+		contextID := 3
+		if copyLength < 5 { // nolint: gomnd // The context id of the short copies is their length - 2.
+			contextID = copyLength - 2
+		}
+
+		r.useBlock(&r.distances)
+
+		distanceCode = br.readSymbol(r.distanceCodes[r.distanceMap[r.distances.blockType<<distanceContextBits|contextID]])
+		distance = r.readDistance(distanceCode)
+	}
+
+	if br.err != nil {
+		return
+	}
+
+	if distance <= 0 {
+		br.corrupt("invalid distance")
+
+		return
+	}
+
+	maxDistance := r.window
+	if r.pos < maxDistance {
+		maxDistance = r.pos
+	}
+
+	if distance > maxDistance {
+		r.copyDictionaryWord(distance-maxDistance-1, copyLength)
+
+		return
+	}
+
+	if copyLength > r.remaining {
+		br.corrupt("invalid copy length")
+
+		return
+	}
+
+	if distanceCode != 0 {
+		r.distIdx++
+		r.dist[r.distIdx&3] = distance
+	}
+
+	start := len(r.out) - distance
+
+	if distance >= copyLength {
+		r.out = append(r.out, r.out[start:start+copyLength]...)
+	} else {
+		// The bytes are repeated when the copy overlaps.
+		for i := 0; i < copyLength; i++ {
+			r.out = append(r.out, r.out[start+i])
+		}
+	}
+
+	r.pos += copyLength
+	r.remaining -= copyLength
+}
+
+// insertLiterals decodes the literals of a command.
+func (r *Reader) insertLiterals(n int) {
+	for i := 0; i < n; i++ {
+		if r.br.err != nil {
+			return
+		}
+
+		r.useBlock(&r.literals)
+
+		blockType := r.literals.blockType
+		contextID := literalContext(r.contextModes[blockType], r.lastByte(1), r.lastByte(2))
+		literal := r.br.readSymbol(r.literalCodes[r.literalMap[blockType<<literalContextBits|contextID]])
+
+		r.out = append(r.out, byte(literal))
+	}
+
+	r.pos += n
+	r.remaining -= n
+}
+
+// useBlock switches to the next block of a category when the current one ends, and counts a symbol of the block.
+func (r *Reader) useBlock(b *blockSwitch) {
+	if b.remaining == 0 {
+		r.switchBlock(b)
+	}
+
+	b.remaining--
+}
+
+// readDistance converts a distance code to the distance.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-4.
+func (r *Reader) readDistance(code int) int {
+	if code < numDistanceShortCodes {
+		return r.dist[(r.distIdx-distanceShortCodeIndex[code])&3] + distanceShortCodeOffset[code]
+	}
+
+	if code < numDistanceShortCodes+r.directCodes {
+		return code - numDistanceShortCodes + 1
+	}
+
+	code -= numDistanceShortCodes + r.directCodes
+	postfix := code & (1<<r.postfixBits - 1)
+	code >>= r.postfixBits
+	extraBits := uint(code>>1) + 1
+	offset := (2+code&1)<<extraBits - 4
+
+	return (offset+int(r.br.readBits(extraBits)))<<r.postfixBits + postfix + r.directCodes + 1
+}
+
+// copyDictionaryWord copies a transformed word of the static dictionary.
+//
+// See https://www.rfc-editor.org/rfc/rfc7932#section-8.
+func (r *Reader) copyDictionaryWord(address, length int) {
+	if length < minWordLength || length > maxWordLength {
+		r.br.corrupt("invalid dictionary word length")
+
+		return
+	}
+
+	sizeBits := dictionarySizeBits[length]
+	wordIdx := address & (1<<sizeBits - 1)
+	transformIdx := address >> sizeBits
+
+	if transformIdx >= len(transforms) {
+		r.br.corrupt("invalid dictionary transform")
+
+		return
+	}
+
+	offset := dictionaryOffsets[length] + wordIdx*length
+	start := len(r.out)
+	r.out = transforms[transformIdx].appendWord(r.out, dictionary[offset:offset+length])
+	n := len(r.out) - start
+
+	if n > r.remaining {
+		r.br.corrupt("invalid dictionary word")
+
+		return
+	}
+
+	r.pos += n
+	r.remaining -= n
+}
+
+// lastByte returns the nth last decoded byte, or zero if there is none.
+func (r *Reader) lastByte(n int) byte {
+	if len(r.out) < n {
+		return 0
+	}
+
+	return r.out[len(r.out)-n]
+}
+
+// NewReader creates a new reader that decompresses the brotli stream of r.
+//
+//	r := brotli.NewReader(resp.Body)
+//	b, err := io.ReadAll(r)
+func NewReader(r io.Reader) *Reader {
+	return &Reader{br: bitReader{r: bufio.NewReader(r)}}
+}
@@ -0,0 +1,184 @@
+//go:build !testsignal
+
+package brotli_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/brotli"
+)
+
+const (
+	sampleHTML       = "../../resources/fixtures/sample.html"
+	sampleHTMLBrotli = "../../resources/fixtures/sample.html.br"
+)
+
+func TestReader_Read(t *testing.T) {
+	t.Parallel()
+
+	html, err := os.ReadFile(sampleHTML)
+	require.NoError(t, err)
+
+	compressedHTML, err := os.ReadFile(sampleHTMLBrotli)
+	require.NoError(t, err)
+
+	large := make([]byte, 200_000)
+
+	for i := range large {
+		large[i] = byte(i * 7 % 251)
+	}
+
+	testCases := []struct {
+		scenario   string
+		compressed []byte
+		expected   []byte
+	}{
+		{
+			scenario:   "empty",
+			compressed: []byte{0x3b},
+			expected:   []byte{},
+		},
+		{
+			scenario:   "repeated bytes",
+			compressed: mustDecodeHex("5b9f86817f02201e0b04b2fc0200"),
+			expected:   make([]byte, 100_000),
+		},
+		{
+			scenario:   "html with dictionary words",
+			compressed: compressedHTML,
+			expected:   html,
+		},
+		{
+			scenario:   "uncompressed meta-blocks",
+			compressed: uncompressedStream(large),
+			expected:   large,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := io.ReadAll(brotli.NewReader(bytes.NewReader(tc.compressed)))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+
+			// The result does not depend on how the compressed stream is split.
+			actual, err = io.ReadAll(brotli.NewReader(iotest.OneByteReader(bytes.NewReader(tc.compressed))))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestReader_Read_Truncated(t *testing.T) {
+	t.Parallel()
+
+	compressed, err := os.ReadFile(sampleHTMLBrotli)
+	require.NoError(t, err)
+
+	for _, n := range []int{0, 1, 10, len(compressed) / 2, len(compressed) - 1} {
+		_, err := io.ReadAll(brotli.NewReader(bytes.NewReader(compressed[:n])))
+
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "truncated at %d", n)
+	}
+}
+
+func TestReader_Read_Corrupted(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		compressed []byte
+		expected   string
+	}{
+		{
+			scenario:   "large window",
+			compressed: []byte{0x11},
+			expected:   "corrupted brotli stream: large window is not supported",
+		},
+		{
+			scenario:   "reserved bit is set",
+			compressed: []byte{0x1c},
+			expected:   "corrupted brotli stream: invalid reserved bit",
+		},
+		{
+			scenario:   "padding is not zero",
+			compressed: []byte{0x8c},
+			expected:   "corrupted brotli stream: invalid padding",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := io.ReadAll(brotli.NewReader(bytes.NewReader(tc.compressed)))
+
+			assert.ErrorIs(t, err, brotli.ErrCorrupted)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestReader_Read_Error(t *testing.T) {
+	t.Parallel()
+
+	readErr := errors.New("read error")
+
+	_, err := io.ReadAll(brotli.NewReader(iotest.ErrReader(readErr)))
+
+	assert.ErrorIs(t, err, readErr)
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// uncompressedStream builds a brotli stream with the default window size that stores the data in uncompressed meta-blocks.
+func uncompressedStream(data []byte) []byte {
+	const maxLength = 1 << 16
+
+	var out []byte
+
+	for i := 0; i < len(data); i += maxLength {
+		n := len(data) - i
+		if n > maxLength {
+			n = maxLength
+		}
+
+		// ISLAST = 0, MNIBBLES = 4, MLEN - 1 and ISUNCOMPRESSED = 1, the stream header is a single zero bit before the first meta-block.
+		header := uint32(n-1)<<3 | 1<<19
+		if i == 0 {
+			header <<= 1
+		}
+
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, data[i:i+n]...)
+	}
+
+	// ISLAST = 1 and ISLASTEMPTY = 1.
+	if len(data) == 0 {
+		return []byte{0x06}
+	}
+
+	return append(out, 0x03)
+}
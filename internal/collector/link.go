@@ -7,7 +7,103 @@ import "io"
 // Ref: https://moz.com/blog/how-many-links-is-too-many
 const initialLinksCapacity = 100
 
+// LinkType is the type of a link that has a special meaning in a document.
+type LinkType string
+
+const (
+	// LinkTypeRefresh is the url of a <meta http-equiv="refresh">.
+	LinkTypeRefresh LinkType = "refresh"
+	// LinkTypeCanonical is the url of a <link rel="canonical">.
+	LinkTypeCanonical LinkType = "canonical"
+	// LinkTypeAlternate is the url of a <link rel="alternate">, for example: a translation of the page with hreflang.
+	LinkTypeAlternate LinkType = "alternate"
+	// LinkTypeOpenGraph is the url of an OpenGraph <meta property="og:url"> or <meta property="og:image">.
+	LinkTypeOpenGraph LinkType = "og"
+	// LinkTypeCSSURL is the url of a CSS url() function, for example: a background image or a font.
+	LinkTypeCSSURL LinkType = "css-url"
+	// LinkTypeCSSImport is the url of a CSS @import rule.
+	LinkTypeCSSImport LinkType = "css-import"
+	// LinkTypeEnclosure is the url of the media of a feed item, such as an RSS <enclosure> or an Atom <link rel="enclosure">.
+	LinkTypeEnclosure LinkType = "enclosure"
+	// LinkTypeSitemap is the url of a nested sitemap in a sitemap index.
+	LinkTypeSitemap LinkType = "sitemap"
+)
+
+// navigationTags are the HTML tags of the links that lead to other pages. The links of the other tags are the assets of the document, such as images,
+// scripts or stylesheets.
+var navigationTags = map[string]struct{}{
+	"a":    {},
+	"area": {},
+}
+
+// navigationTypes are the link types that lead to other pages.
+var navigationTypes = map[LinkType]struct{}{
+	LinkTypeRefresh:   {},
+	LinkTypeCanonical: {},
+	LinkTypeAlternate: {},
+}
+
+// Link is a link collected from a document.
+type Link struct {
+	URL string
+	// Tag is the HTML tag that the link comes from. It is empty if the document is not HTML.
+	Tag string
+	// Type is the special meaning of the link in the document. It is empty for the other links.
+	Type LinkType
+	// Rel is the lowercase rel keywords of the link, for example: nofollow, sponsored or ugc. It is nil if the link does not have a rel attribute.
+	Rel []string
+	// Text is the visible text of an <a> element, with the whitespaces collapsed. It is empty if the element does not have any text, for example: an
+	// image link.
+	Text string
+	// Title is the title attribute of the tag.
+	Title string
+	// AriaLabel is the aria-label attribute of the tag.
+	AriaLabel string
+}
+
+// HasRel checks whether the link has the rel keyword.
+func (l Link) HasRel(rel string) bool {
+	return hasToken(l.Rel, rel)
+}
+
+// IsNavigation checks whether the link leads to another page rather than an asset of the document. The links without a type that are not from an HTML
+// tag are considered navigation links.
+func (l Link) IsNavigation() bool {
+	if l.Type != "" {
+		_, ok := navigationTypes[l.Type]
+
+		return ok
+	}
+
+	if l.Tag == "" {
+		return true
+	}
+
+	_, ok := navigationTags[l.Tag]
+
+	return ok
+}
+
+// Document is the links collected from a document.
+type Document struct {
+	// Base is the url that the document declares for resolving its relative links, for example: the <base href> in HTML. It could be relative as well. It
+	// is empty if the document does not declare one.
+	Base string
+	// NoFollow is true if the document asks the crawlers not to follow its links, for example: <meta name="robots" content="nofollow"> in HTML.
+	NoFollow bool
+	Links    []Link
+}
+
 // LinkCollector is a collector that collects links from a reader.
 type LinkCollector interface {
-	GetLinks(r io.Reader) ([]string, error)
+	GetLinks(r io.Reader) (*Document, error)
+}
+
+// appendLinks appends the urls to the links, without the tag.
+func appendLinks(links []Link, urls []string) []Link {
+	for _, u := range urls {
+		links = append(links, Link{URL: u})
+	}
+
+	return links
 }
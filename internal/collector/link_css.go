@@ -0,0 +1,254 @@
+package collector
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var _ LinkCollector = (*CSSLinkCollector)(nil)
+
+// CSSLinkCollector is a collector that collects links from a reader of a CSS document.
+//
+//	c := NewCSSLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+type CSSLinkCollector struct{}
+
+// GetLinks collects the links of the url() functions and the @import rules from a reader of a CSS document.
+func (c CSSLinkCollector) GetLinks(r io.Reader) (*Document, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not collect links from css doc: %w", err)
+	}
+
+	links := appendCSSLinks(make([]Link, 0, initialLinksCapacity), string(b), "")
+
+	// Reduce memory allocation. GC will clean up the old links slice.
+	result := make([]Link, len(links))
+	copy(result, links)
+
+	return &Document{Links: result}, nil
+}
+
+// appendCSSLinks appends the links of the url() functions and the @import rules of a stylesheet, or of the declarations of a style attribute. The tag is
+// the HTML tag that the stylesheet comes from, it is empty for a CSS document.
+//
+// The data urls and the references to the elements of the document, such as `url(#filter)`, are not links, so they are skipped.
+func appendCSSLinks(links []Link, css, tag string) []Link {
+	// inImport is true after an @import keyword, until its url is read or the rule ends.
+	inImport := false
+	pos := 0
+
+	appendLink := func(u string) {
+		linkType := LinkTypeCSSURL
+
+		if inImport {
+			linkType = LinkTypeCSSImport
+			inImport = false
+		}
+
+		if u = strings.TrimSpace(u); u == "" || strings.HasPrefix(u, "#") || hasPrefixFold(u, "data:") {
+			return
+		}
+
+		links = append(links, Link{URL: u, Tag: tag, Type: linkType})
+	}
+
+	for pos < len(css) {
+		switch ch := css[pos]; {
+		case strings.HasPrefix(css[pos:], "/*"):
+			end := strings.Index(css[pos+2:], "*/")
+			if end < 0 {
+				return links
+			}
+
+			pos += end + 4
+
+		case ch == '"' || ch == '\'':
+			s, next := readCSSString(css, pos)
+			pos = next
+
+			// Only the string of an @import is a link, the others are just values, such as `content: "url(a.png)"`.
+			if inImport {
+				appendLink(s)
+			}
+
+		case ch == '@' && hasPrefixFold(css[pos+1:], "import") && !isCSSNameChar(byteAt(css, pos+7)):
+			inImport = true
+			pos += 7
+
+		case ch == ';' || ch == '{' || ch == '}':
+			inImport = false
+			pos++
+
+		case (ch == 'u' || ch == 'U') && hasPrefixFold(css[pos:], "url(") && (pos == 0 || !isCSSNameChar(css[pos-1])):
+			u, next := readCSSURL(css, pos+4)
+			pos = next
+
+			appendLink(u)
+
+		case ch == '\\':
+			// Skip the escaped character, so that an escaped quote does not start a string.
+			pos += 2
+
+		default:
+			pos++
+		}
+	}
+
+	return links
+}
+
+// readCSSString reads the quoted string that starts at the position, and returns its unescaped value and the position after the closing quote. An
+// unterminated string ends at the end of the line, the same as browsers do.
+func readCSSString(css string, pos int) (string, int) {
+	quote := css[pos]
+	sb := new(strings.Builder)
+	pos++
+
+	for pos < len(css) {
+		switch ch := css[pos]; {
+		case ch == quote:
+			return sb.String(), pos + 1
+
+		case ch == '\n':
+			return sb.String(), pos
+
+		case ch == '\\':
+			var r string
+
+			r, pos = readCSSEscape(css, pos)
+			sb.WriteString(r)
+
+		default:
+			sb.WriteByte(ch)
+			pos++
+		}
+	}
+
+	return sb.String(), pos
+}
+
+// readCSSURL reads the argument of a url() function that starts at the position, right after the opening parenthesis. It returns the unescaped url and
+// the position after the closing parenthesis.
+func readCSSURL(css string, pos int) (string, int) {
+	for pos < len(css) && isHTMLSpace(css[pos]) {
+		pos++
+	}
+
+	if pos < len(css) && (css[pos] == '"' || css[pos] == '\'') {
+		u, next := readCSSString(css, pos)
+
+		if end := strings.IndexByte(css[next:], ')'); end >= 0 {
+			return u, next + end + 1
+		}
+
+		return u, len(css)
+	}
+
+	sb := new(strings.Builder)
+
+	for pos < len(css) {
+		switch ch := css[pos]; {
+		case ch == ')':
+			return sb.String(), pos + 1
+
+		case ch == '\\':
+			var r string
+
+			r, pos = readCSSEscape(css, pos)
+			sb.WriteString(r)
+
+		default:
+			sb.WriteByte(ch)
+			pos++
+		}
+	}
+
+	return sb.String(), pos
+}
+
+// readCSSEscape reads the escape sequence that starts at the position, and returns the escaped character and the position after the sequence. A hex
+// escape, such as `\29 `, is up to 6 hex digits and an optional whitespace.
+//
+// See https://www.w3.org/TR/css-syntax-3/#consume-escaped-code-point.
+func readCSSEscape(css string, pos int) (string, int) {
+	pos++
+
+	if pos >= len(css) {
+		return "", pos
+	}
+
+	end := pos
+	for end < len(css) && end-pos < 6 && isHexDigit(css[end]) {
+		end++
+	}
+
+	if end == pos {
+		// An escaped new line is a line continuation in a string.
+		if css[pos] == '\n' {
+			return "", pos + 1
+		}
+
+		_, size := utf8.DecodeRuneInString(css[pos:])
+
+		return css[pos : pos+size], pos + size
+	}
+
+	code, _ := strconv.ParseUint(css[pos:end], 16, 32) // nolint: errcheck // The digits are validated.
+
+	if end < len(css) && isHTMLSpace(css[end]) {
+		end++
+	}
+
+	r := rune(code)
+	if r == 0 || !utf8.ValidRune(r) {
+		r = utf8.RuneError
+	}
+
+	return string(r), end
+}
+
+// isHexDigit checks whether the character is a hex digit.
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isCSSNameChar checks whether the character could be a part of a CSS identifier, for example: the `-` in `my-url(`.
+func isCSSNameChar(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// byteAt returns the character at the position, or 0 if the position is out of range.
+func byteAt(s string, pos int) byte {
+	if pos >= len(s) {
+		return 0
+	}
+
+	return s[pos]
+}
+
+// hasPrefixFold checks whether the string starts with the prefix, case-insensitively.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// NewCSSLinkCollector creates a new collector for collecting links from a CSS document.
+//
+//	c := NewCSSLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+func NewCSSLinkCollector() *CSSLinkCollector {
+	return &CSSLinkCollector{}
+}
@@ -0,0 +1,123 @@
+//go:build !testsignal
+
+package collector_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+)
+
+const sampleCSS = "../../resources/fixtures/sample.css"
+
+func TestCSSLinkCollector_GetLinks_Error(t *testing.T) {
+	t.Parallel()
+
+	c := collector.NewCSSLinkCollector()
+
+	actual, err := c.GetLinks(newErrorReader(errors.New("random error")))
+
+	assert.EqualError(t, err, "could not collect links from css doc: random error")
+	assert.Nil(t, actual)
+}
+
+func TestCSSLinkCollector_GetLinks_Success(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Clean(sampleCSS))
+	require.NoError(t, err, "could not open css fixture")
+
+	defer f.Close() // nolint: errcheck,gosec
+
+	c := collector.NewCSSLinkCollector()
+
+	actual, err := c.GetLinks(f)
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "base.css", Type: collector.LinkTypeCSSImport},
+		{URL: "print.css", Type: collector.LinkTypeCSSImport},
+		{URL: "theme.css", Type: collector.LinkTypeCSSImport},
+		{URL: "/fonts/sample.woff2", Type: collector.LinkTypeCSSURL},
+		{URL: "/fonts/sample.woff", Type: collector.LinkTypeCSSURL},
+		{URL: "images/background.png", Type: collector.LinkTypeCSSURL},
+		{URL: "https://cdn.example.com/mask.svg#icon", Type: collector.LinkTypeCSSURL},
+		{URL: "images/open(1).png", Type: collector.LinkTypeCSSURL},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestCSSLinkCollector_GetLinks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		css      string
+		expected []collector.Link
+	}{
+		{
+			scenario: "empty",
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "no url",
+			css:      `body { color: red; }`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "empty url",
+			css:      `a { background: url(); b: url(  ""  ) }`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "function that ends with url",
+			css:      `a { background: my-url(a.png); b: src(b.png) }`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "hex escape",
+			css:      `a { background: url("\2f a\20 b.png") }`,
+			expected: []collector.Link{{URL: "/a b.png", Type: collector.LinkTypeCSSURL}},
+		},
+		{
+			scenario: "unterminated url",
+			css:      `a { background: url(a.png`,
+			expected: []collector.Link{{URL: "a.png", Type: collector.LinkTypeCSSURL}},
+		},
+		{
+			scenario: "unterminated comment",
+			css:      `a { background: url(a.png) } /* url(b.png)`,
+			expected: []collector.Link{{URL: "a.png", Type: collector.LinkTypeCSSURL}},
+		},
+		{
+			scenario: "import ends at semicolon",
+			css:      `@import; a { content: "a.css" }`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "not import",
+			css:      `@important "a.css";`,
+			expected: []collector.Link{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := collector.NewCSSLinkCollector().GetLinks(strings.NewReader(tc.css))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
+}
@@ -14,20 +14,36 @@ var _ LinkCollector = (*HTMLLinkCollector)(nil)
 // HTMLLinkCollector is a collector that collects links from a reader of an HTML document.
 //
 //    c := NewHTMLLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
+//    fmt.Println(doc.Links)
 type HTMLLinkCollector struct {
-	tagAttributes map[string]string // Key is tag name, Value is attribute name.
+	tagAttributes map[string][]string // Key is tag name, Value is attribute names.
 }
 
 // GetLinks collects links from a reader of an HTML document.
-func (c HTMLLinkCollector) GetLinks(r io.Reader) ([]string, error) {
+//
+// The text of an <a> element is collected along with its link, as well as the title and the aria-label of every tag.
+//
+// The links of the url() functions and the @import rules of the <style> elements and the style attributes are collected as well, see CSSLinkCollector.
+//
+// The href of the first <base> element is the base of the document, as browsers use it for resolving every relative link.
+//
+// The links of <meta http-equiv="refresh">, <link rel="canonical">, <link rel="alternate">, <meta property="og:url"> and <meta property="og:image"> are
+// always collected, with their types.
+func (c HTMLLinkCollector) GetLinks(r io.Reader) (*Document, error) {
 	z := html.NewTokenizer(r)
-	links := make([]string, 0, initialLinksCapacity)
+	links := make([]Link, 0, initialLinksCapacity)
+	doc := &Document{}
+	hasBase := false
+
+	// anchor is the index of the link of the <a> element that is being read, -1 if there is none.
+	anchor := -1
+	anchorText := new(strings.Builder)
+	inStyle := false
 
 process:
 	for {
@@ -39,42 +55,277 @@ process:
 
 			return nil, fmt.Errorf("could not collect links from html doc: %w", z.Err())
 
+		case html.TextToken:
+			if inStyle {
+				links = appendCSSLinks(links, string(z.Text()), "style")
+			} else if anchor >= 0 {
+				anchorText.Write(z.Text())
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+
+			switch string(name) {
+			case "a":
+				if anchor >= 0 {
+					links[anchor].Text = collapseSpaces(anchorText.String())
+					anchor = -1
+				}
+
+			case "style":
+				inStyle = false
+			}
+
 		case html.StartTagToken, html.SelfClosingTagToken:
 			tag := z.Token()
-			if wantAttr, ok := c.tagAttributes[tag.Data]; ok {
-				for _, attr := range tag.Attr {
-					if attr.Key == wantAttr {
-						// In HTML, \n does not mean new line. Browser will ignore it, so link like "\nhttps://example.org/\npath" will be interpreted
-						// as "https://example.org/path".
-						links = append(links, strings.ReplaceAll(attr.Val, "\n", ""))
-
-						break
-					}
+
+			// An <a> element ends when another one starts, the same as browsers do.
+			if tag.Data == "a" && anchor >= 0 {
+				links[anchor].Text = collapseSpaces(anchorText.String())
+				anchor = -1
+			}
+
+			if tag.Data == "style" && tt == html.StartTagToken {
+				inStyle = true
+			}
+
+			if tag.Data == "base" && !hasBase {
+				doc.Base, hasBase = baseHref(tag)
+			}
+
+			if tag.Data == "meta" && isRobotsNoFollow(tag) {
+				doc.NoFollow = true
+			}
+
+			if l, ok := typedLink(tag); ok {
+				links = append(links, l)
+
+				continue
+			}
+
+			if wantAttrs, ok := c.tagAttributes[tag.Data]; ok {
+				numLinks := len(links)
+				links = appendTagLinks(links, tag, wantAttrs)
+
+				if tag.Data == "a" && tt == html.StartTagToken && len(links) > numLinks {
+					anchor = len(links) - 1
+					anchorText.Reset()
 				}
 			}
+
+			if style := attrValue(tag, "style"); style != "" {
+				links = appendCSSLinks(links, style, tag.Data)
+			}
 		}
 	}
 
+	// The document ends without closing the <a> element.
+	if anchor >= 0 {
+		links[anchor].Text = collapseSpaces(anchorText.String())
+	}
+
 	// Reduce memory allocation. GC will clean up the old links slice.
-	result := make([]string, len(links))
-	copy(result, links)
+	doc.Links = make([]Link, len(links))
+	copy(doc.Links, links)
+
+	return doc, nil
+}
+
+// typedLink returns the link of the tag if it has a special meaning in the document, see LinkType.
+func typedLink(tag html.Token) (Link, bool) {
+	switch tag.Data {
+	case "link":
+		rel := relKeywords(tag)
+		href := strings.ReplaceAll(attrValue(tag, "href"), "\n", "")
+
+		if href == "" {
+			return Link{}, false
+		}
+
+		switch {
+		case hasToken(rel, "canonical"):
+			return Link{URL: href, Tag: tag.Data, Type: LinkTypeCanonical, Rel: rel}, true
+
+		// An alternate stylesheet is still a stylesheet.
+		case hasToken(rel, "alternate") && !hasToken(rel, "stylesheet"):
+			return Link{URL: href, Tag: tag.Data, Type: LinkTypeAlternate, Rel: rel}, true
+		}
+
+	case "meta":
+		content := attrValue(tag, "content")
+
+		if strings.EqualFold(attrValue(tag, "http-equiv"), "refresh") {
+			u, ok := parseMetaRefresh(content)
+
+			return Link{URL: u, Tag: tag.Data, Type: LinkTypeRefresh}, ok
+		}
+
+		// Some websites use the name instead of the property.
+		property := attrValue(tag, "property")
+		if property == "" {
+			property = attrValue(tag, "name")
+		}
+
+		if content = strings.TrimSpace(content); content != "" && (property == "og:url" || property == "og:image") {
+			return Link{URL: content, Tag: tag.Data, Type: LinkTypeOpenGraph}, true
+		}
+	}
+
+	return Link{}, false
+}
+
+// relKeywords returns the lowercase keywords of the rel attribute of the tag, or nil if there is none.
+func relKeywords(tag html.Token) []string {
+	rel := strings.Fields(strings.ToLower(attrValue(tag, "rel")))
+	if len(rel) == 0 {
+		return nil
+	}
 
-	return result, nil
+	return rel
+}
+
+// isRobotsNoFollow checks whether the tag is a <meta name="robots"> that asks the crawlers not to follow the links of the document. The `none` directive
+// is the same as `noindex, nofollow`.
+func isRobotsNoFollow(tag html.Token) bool {
+	if !strings.EqualFold(attrValue(tag, "name"), "robots") {
+		return false
+	}
+
+	directives := strings.Fields(strings.ReplaceAll(strings.ToLower(attrValue(tag, "content")), ",", " "))
+
+	return hasToken(directives, "nofollow") || hasToken(directives, "none")
+}
+
+// collapseSpaces trims the whitespaces of the text and collapses the ones in between into a single space, the same as browsers render the text.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// attrValue returns the value of the first attribute of the tag with the key, or an empty string if there is none.
+func attrValue(tag html.Token, key string) string {
+	for _, attr := range tag.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// hasToken checks whether the token is in the list.
+func hasToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// baseHref returns the href of a <base> element. The element is ignored if it does not have an href, for example: <base target="_blank">.
+func baseHref(tag html.Token) (string, bool) {
+	for _, attr := range tag.Attr {
+		if attr.Key == "href" {
+			return strings.TrimSpace(strings.ReplaceAll(attr.Val, "\n", "")), true
+		}
+	}
+
+	return "", false
+}
+
+// appendTagLinks appends the links of the wanted attributes of the tag. Only the first occurrence of an attribute is collected.
+//
+// A srcset attribute is split into the urls of its image candidates, each of them is a link.
+func appendTagLinks(links []Link, tag html.Token, wantAttrs []string) []Link {
+	link := Link{
+		Tag:       tag.Data,
+		Rel:       relKeywords(tag),
+		Title:     collapseSpaces(attrValue(tag, "title")),
+		AriaLabel: collapseSpaces(attrValue(tag, "aria-label")),
+	}
+
+	for _, wantAttr := range wantAttrs {
+		for _, attr := range tag.Attr {
+			if attr.Key != wantAttr {
+				continue
+			}
+
+			if isSrcsetAttribute(attr.Key) {
+				for _, u := range parseSrcset(attr.Val) {
+					link.URL = u
+					links = append(links, link)
+				}
+
+				break
+			}
+
+			// In HTML, \n does not mean new line. Browser will ignore it, so link like "\nhttps://example.org/\npath" will be interpreted
+			// as "https://example.org/path".
+			link.URL = strings.ReplaceAll(attr.Val, "\n", "")
+			links = append(links, link)
+
+			break
+		}
+	}
+
+	return links
 }
 
 // NewHTMLLinkCollector creates a new collector for collecting links from an HTML document.
 //
+// By default, the links are collected from a[href], area[href], img[src], img[srcset], script[src], link[href], iframe[src], form[action], source[src],
+// source[srcset], video[poster] and object[data]. Use WithTagAttributes to change that.
+//
 //    c := NewHTMLLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
-func NewHTMLLinkCollector() *HTMLLinkCollector {
-	return &HTMLLinkCollector{
-		tagAttributes: map[string]string{
-			"a": "href",
+//    fmt.Println(doc.Links)
+func NewHTMLLinkCollector(opts ...HTMLLinkCollectorOption) *HTMLLinkCollector {
+	c := &HTMLLinkCollector{
+		tagAttributes: map[string][]string{
+			"a":      {"href"},
+			"area":   {"href"},
+			"img":    {"src", "srcset"},
+			"script": {"src"},
+			"link":   {"href"},
+			"iframe": {"src"},
+			"form":   {"action"},
+			"source": {"src", "srcset"},
+			"video":  {"poster"},
+			"object": {"data"},
 		},
 	}
+
+	for _, opt := range opts {
+		opt.applyHTMLLinkCollectorOption(c)
+	}
+
+	return c
+}
+
+// HTMLLinkCollectorOption is option to set up HTMLLinkCollector.
+type HTMLLinkCollectorOption interface {
+	applyHTMLLinkCollectorOption(c *HTMLLinkCollector)
+}
+
+type htmlLinkCollectorOptionFunc func(c *HTMLLinkCollector)
+
+func (f htmlLinkCollectorOptionFunc) applyHTMLLinkCollectorOption(c *HTMLLinkCollector) {
+	f(c)
+}
+
+// WithTagAttributes sets the tags and their attributes that HTMLLinkCollector collects the links from, replacing the default ones.
+//
+//    c := NewHTMLLinkCollector(WithTagAttributes(map[string][]string{
+//    	"a":   {"href"},
+//    	"img": {"src"},
+//    }))
+func WithTagAttributes(tagAttributes map[string][]string) HTMLLinkCollectorOption {
+	return htmlLinkCollectorOptionFunc(func(c *HTMLLinkCollector) {
+		c.tagAttributes = tagAttributes
+	})
 }
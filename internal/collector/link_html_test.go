@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,23 +41,554 @@ func TestHTMLLinkCollector_GetLinks_Success(t *testing.T) {
 	actual, err := c.GetLinks(f)
 	require.NoError(t, err, "could not get links")
 
-	expected := []string{
-		"http://google.com",
-		"http://www.google.com",
-		"https://google.com",
-		"https://www.google.com",
-		"/",
-		"/absolute/path",
-		"relative/path",
-		"#anchor",
-		"?message=hello%20world",
-		".",
-		"",
-		"https://example.org/link-is-broken",
-		"javascript:alert('hello')",
-		"mailto:john@example.com",
-		"http://www.bing.com",
-	}
-
-	assert.Equal(t, expected, actual)
+	expected := []collector.Link{
+		{URL: "http://google.com", Tag: "a", Text: "http without www"},
+		{URL: "http://www.google.com", Tag: "a", Text: "http with www"},
+		{URL: "https://google.com", Tag: "a", Text: "https without www"},
+		{URL: "https://www.google.com", Tag: "a", Text: "https with www"},
+		{URL: "/", Tag: "a", Text: "Root"},
+		{URL: "/absolute/path", Tag: "a", Text: "Absolute path"},
+		{URL: "relative/path", Tag: "a", Text: "Relative path"},
+		{URL: "#anchor", Tag: "a", Text: "Only Anchor"},
+		{URL: "?message=hello%20world", Tag: "a", Text: "Encoded"},
+		{URL: ".", Tag: "a", Text: "Dot"},
+		{URL: "", Tag: "a", Text: "Empty"},
+		{URL: "https://example.org/link-is-broken", Tag: "a", Text: "Link is broken into multiple lines"},
+		{URL: "javascript:alert('hello')", Tag: "a", Text: "Javascript"},
+		{URL: "mailto:john@example.com", Tag: "a", Text: "Email"},
+		{URL: "http://www.bing.com", Tag: "a", Text: "Bing"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestHTMLLinkCollector_GetLinks_DefaultTags(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<link rel="stylesheet" href="/style.css">
+		<script src="/script.js"></script>
+		<a href="/page">Page</a>
+		<map><area href="/area" alt="Area"></map>
+		<img src="/image.png" alt="Image">
+		<iframe src="/frame"></iframe>
+		<form action="/submit"></form>
+		<video poster="/poster.jpg"><source src="/video.mp4"></video>
+		<object data="/object.swf"></object>
+		<embed src="/embed.swf">
+	`
+
+	c := collector.NewHTMLLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/style.css", Tag: "link", Rel: []string{"stylesheet"}},
+		{URL: "/script.js", Tag: "script"},
+		{URL: "/page", Tag: "a", Text: "Page"},
+		{URL: "/area", Tag: "area"},
+		{URL: "/image.png", Tag: "img"},
+		{URL: "/frame", Tag: "iframe"},
+		{URL: "/submit", Tag: "form"},
+		{URL: "/poster.jpg", Tag: "video"},
+		{URL: "/video.mp4", Tag: "source"},
+		{URL: "/object.swf", Tag: "object"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestHTMLLinkCollector_GetLinks_WithTagAttributes(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<a href="/page">Page</a>
+		<img src="/image.png" alt="Image">
+		<embed src="/embed.swf" data-fallback="/fallback.png">
+	`
+
+	c := collector.NewHTMLLinkCollector(collector.WithTagAttributes(map[string][]string{
+		"img":   {"src"},
+		"embed": {"src", "data-fallback"},
+	}))
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/image.png", Tag: "img"},
+		{URL: "/embed.swf", Tag: "embed"},
+		{URL: "/fallback.png", Tag: "embed"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestLink_IsNavigation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		link     collector.Link
+		expected bool
+	}{
+		{
+			scenario: "anchor",
+			link:     collector.Link{URL: "/page", Tag: "a"},
+			expected: true,
+		},
+		{
+			scenario: "area",
+			link:     collector.Link{URL: "/page", Tag: "area"},
+			expected: true,
+		},
+		{
+			scenario: "not from html",
+			link:     collector.Link{URL: "https://example.com/"},
+			expected: true,
+		},
+		{
+			scenario: "image",
+			link:     collector.Link{URL: "/image.png", Tag: "img"},
+			expected: false,
+		},
+		{
+			scenario: "stylesheet",
+			link:     collector.Link{URL: "/style.css", Tag: "link"},
+			expected: false,
+		},
+		{
+			scenario: "canonical",
+			link:     collector.Link{URL: "/page", Tag: "link", Type: collector.LinkTypeCanonical},
+			expected: true,
+		},
+		{
+			scenario: "refresh",
+			link:     collector.Link{URL: "/page", Tag: "meta", Type: collector.LinkTypeRefresh},
+			expected: true,
+		},
+		{
+			scenario: "og image",
+			link:     collector.Link{URL: "/image.png", Tag: "meta", Type: collector.LinkTypeOpenGraph},
+			expected: false,
+		},
+		{
+			scenario: "css url in style",
+			link:     collector.Link{URL: "/image.png", Tag: "style", Type: collector.LinkTypeCSSURL},
+			expected: false,
+		},
+		{
+			scenario: "css import in css document",
+			link:     collector.Link{URL: "/base.css", Type: collector.LinkTypeCSSImport},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.link.IsNavigation())
+		})
+	}
+}
+
+func TestHTMLLinkCollector_GetLinks_Srcset(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		doc      string
+		expected []collector.Link
+	}{
+		{
+			scenario: "density descriptors",
+			doc:      `<img srcset="a.jpg 1x, b.jpg 2x">`,
+			expected: []collector.Link{
+				{URL: "a.jpg", Tag: "img"},
+				{URL: "b.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "width descriptors and src",
+			doc:      `<img src="fallback.jpg" srcset="/small.jpg 480w, /large.jpg 1080w" sizes="50vw">`,
+			expected: []collector.Link{
+				{URL: "fallback.jpg", Tag: "img"},
+				{URL: "/small.jpg", Tag: "img"},
+				{URL: "/large.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "no descriptor",
+			doc:      `<img srcset="a.jpg">`,
+			expected: []collector.Link{
+				{URL: "a.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "url ends with comma",
+			doc:      `<img srcset="a.jpg,, b.jpg 2x">`,
+			expected: []collector.Link{
+				{URL: "a.jpg", Tag: "img"},
+				{URL: "b.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "comma without whitespace is a part of url",
+			doc:      `<img srcset="a.jpg,b.jpg 2x">`,
+			expected: []collector.Link{
+				{URL: "a.jpg,b.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "comma in url",
+			doc:      `<img srcset="/image?size=1,2 1x, /image?size=3,4 2x">`,
+			expected: []collector.Link{
+				{URL: "/image?size=1,2", Tag: "img"},
+				{URL: "/image?size=3,4", Tag: "img"},
+			},
+		},
+		{
+			scenario: "comma in parentheses of descriptor",
+			doc:      `<img srcset="a.jpg 1x (foo, bar), b.jpg 2x">`,
+			expected: []collector.Link{
+				{URL: "a.jpg", Tag: "img"},
+				{URL: "b.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "extra whitespaces and commas",
+			doc: `<img srcset="
+				, a.jpg   1x ,,
+				b.jpg	2x ,
+			">`,
+			expected: []collector.Link{
+				{URL: "a.jpg", Tag: "img"},
+				{URL: "b.jpg", Tag: "img"},
+			},
+		},
+		{
+			scenario: "empty",
+			doc:      `<img srcset=" , ">`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "source in picture",
+			doc:      `<picture><source srcset="a.webp 1x, b.webp 2x" type="image/webp"><img src="a.jpg"></picture>`,
+			expected: []collector.Link{
+				{URL: "a.webp", Tag: "source"},
+				{URL: "b.webp", Tag: "source"},
+				{URL: "a.jpg", Tag: "img"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := collector.NewHTMLLinkCollector().GetLinks(strings.NewReader(tc.doc))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
+}
+
+func TestHTMLLinkCollector_GetLinks_Base(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		doc      string
+		expected string
+	}{
+		{
+			scenario: "no base",
+			doc:      `<a href="page">Page</a>`,
+		},
+		{
+			scenario: "base",
+			doc:      `<head><base href="https://example.com/dir/"></head><a href="page">Page</a>`,
+			expected: "https://example.com/dir/",
+		},
+		{
+			scenario: "relative base",
+			doc:      `<head><base href=" /dir/ "></head><a href="page">Page</a>`,
+			expected: "/dir/",
+		},
+		{
+			scenario: "only the first base is used",
+			doc:      `<head><base href="/first/"><base href="/second/"></head>`,
+			expected: "/first/",
+		},
+		{
+			scenario: "base without href is ignored",
+			doc:      `<head><base target="_blank"><base href="/dir/"></head>`,
+			expected: "/dir/",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := collector.NewHTMLLinkCollector().GetLinks(strings.NewReader(tc.doc))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Base)
+		})
+	}
+}
+
+func TestHTMLLinkCollector_GetLinks_TypedLinks(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<head>
+			<meta http-equiv="Refresh" content="5; url=/next">
+			<link rel="canonical" href="https://example.com/page">
+			<link rel="alternate" hreflang="fr" href="https://example.com/fr/page">
+			<link rel="alternate stylesheet" href="/dark.css">
+			<link rel="stylesheet" href="/style.css">
+			<meta property="og:url" content="https://example.com/page">
+			<meta property="og:image" content=" https://example.com/image.png ">
+			<meta name="og:image" content="/name-image.png">
+			<meta property="og:title" content="Title is not a link">
+		</head>
+		<a href="/page">Page</a>
+	`
+
+	c := collector.NewHTMLLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/next", Tag: "meta", Type: collector.LinkTypeRefresh},
+		{URL: "https://example.com/page", Tag: "link", Type: collector.LinkTypeCanonical, Rel: []string{"canonical"}},
+		{URL: "https://example.com/fr/page", Tag: "link", Type: collector.LinkTypeAlternate, Rel: []string{"alternate"}},
+		{URL: "/dark.css", Tag: "link", Rel: []string{"alternate", "stylesheet"}},
+		{URL: "/style.css", Tag: "link", Rel: []string{"stylesheet"}},
+		{URL: "https://example.com/page", Tag: "meta", Type: collector.LinkTypeOpenGraph},
+		{URL: "https://example.com/image.png", Tag: "meta", Type: collector.LinkTypeOpenGraph},
+		{URL: "/name-image.png", Tag: "meta", Type: collector.LinkTypeOpenGraph},
+		{URL: "/page", Tag: "a", Text: "Page"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestHTMLLinkCollector_GetLinks_Rel(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<a href="/followed">Followed</a>
+		<a href="/nofollow" rel="NoFollow">NoFollow</a>
+		<a href="/sponsored" rel=" sponsored  nofollow ">Sponsored</a>
+		<area href="/ugc" rel="ugc">
+	`
+
+	c := collector.NewHTMLLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/followed", Tag: "a", Text: "Followed"},
+		{URL: "/nofollow", Tag: "a", Rel: []string{"nofollow"}, Text: "NoFollow"},
+		{URL: "/sponsored", Tag: "a", Rel: []string{"sponsored", "nofollow"}, Text: "Sponsored"},
+		{URL: "/ugc", Tag: "area", Rel: []string{"ugc"}},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+	assert.False(t, actual.NoFollow)
+	assert.True(t, actual.Links[2].HasRel("nofollow"))
+	assert.False(t, actual.Links[3].HasRel("nofollow"))
+}
+
+func TestHTMLLinkCollector_GetLinks_Text(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<a href="/page">
+			Read <b>more</b>
+			about &amp; around
+		</a>
+		<a href="/empty"></a>
+		<a href="/image" aria-label="Home page"><img src="/logo.png" alt="Logo" title="Logo"></a>
+		<a href="/title" title=" Next  page ">Next</a>
+		<a href="/unclosed">Unclosed <a href="/next">Next</a>
+		<a name="no-href">Not a link</a>
+		<area href="/area" title="Area">
+		<a href="/last">Last
+	`
+
+	c := collector.NewHTMLLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/page", Tag: "a", Text: "Read more about & around"},
+		{URL: "/empty", Tag: "a"},
+		{URL: "/image", Tag: "a", AriaLabel: "Home page"},
+		{URL: "/logo.png", Tag: "img", Title: "Logo"},
+		{URL: "/title", Tag: "a", Text: "Next", Title: "Next page"},
+		{URL: "/unclosed", Tag: "a", Text: "Unclosed"},
+		{URL: "/next", Tag: "a", Text: "Next"},
+		{URL: "/area", Tag: "area", Title: "Area"},
+		{URL: "/last", Tag: "a", Text: "Last"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestHTMLLinkCollector_GetLinks_Style(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+		<style>
+			@import "/base.css";
+			body { background: url("/background.png") }
+			a > b { content: "&amp;" }
+		</style>
+		<div style="background-image: url(/div.png)">Text</div>
+		<a href="/page" style="background: url('/icon.png')">Page</a>
+		<p style="color: red">No links</p>
+	`
+
+	c := collector.NewHTMLLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "/base.css", Tag: "style", Type: collector.LinkTypeCSSImport},
+		{URL: "/background.png", Tag: "style", Type: collector.LinkTypeCSSURL},
+		{URL: "/div.png", Tag: "div", Type: collector.LinkTypeCSSURL},
+		{URL: "/page", Tag: "a", Text: "Page"},
+		{URL: "/icon.png", Tag: "a", Type: collector.LinkTypeCSSURL},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestHTMLLinkCollector_GetLinks_RobotsNoFollow(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		doc      string
+		expected bool
+	}{
+		{
+			scenario: "no meta robots",
+			doc:      `<a href="/page">Page</a>`,
+		},
+		{
+			scenario: "index follow",
+			doc:      `<meta name="robots" content="index, follow">`,
+		},
+		{
+			scenario: "nofollow",
+			doc:      `<meta name="robots" content="nofollow">`,
+			expected: true,
+		},
+		{
+			scenario: "noindex nofollow without space",
+			doc:      `<meta name="ROBOTS" content="noindex,NOFOLLOW">`,
+			expected: true,
+		},
+		{
+			scenario: "none",
+			doc:      `<meta name="robots" content="none">`,
+			expected: true,
+		},
+		{
+			scenario: "other bot",
+			doc:      `<meta name="description" content="nofollow">`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := collector.NewHTMLLinkCollector().GetLinks(strings.NewReader(tc.doc))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.NoFollow)
+		})
+	}
+}
+
+func TestHTMLLinkCollector_GetLinks_MetaRefresh(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		content  string
+		expected []collector.Link
+	}{
+		{
+			scenario: "url",
+			content:  "0;url=https://example.com/",
+			expected: []collector.Link{{URL: "https://example.com/", Tag: "meta", Type: collector.LinkTypeRefresh}},
+		},
+		{
+			scenario: "uppercase url with spaces",
+			content:  " 1.5 , URL = /next ",
+			expected: []collector.Link{{URL: "/next", Tag: "meta", Type: collector.LinkTypeRefresh}},
+		},
+		{
+			scenario: "quoted url",
+			content:  `0; url='/next?a=1' ignored`,
+			expected: []collector.Link{{URL: "/next?a=1", Tag: "meta", Type: collector.LinkTypeRefresh}},
+		},
+		{
+			scenario: "url without prefix",
+			content:  "0; /next",
+			expected: []collector.Link{{URL: "/next", Tag: "meta", Type: collector.LinkTypeRefresh}},
+		},
+		{
+			scenario: "url without equal sign",
+			content:  "0; urlpath",
+			expected: []collector.Link{{URL: "urlpath", Tag: "meta", Type: collector.LinkTypeRefresh}},
+		},
+		{
+			scenario: "no url",
+			content:  "5",
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "empty url",
+			content:  "0; url=",
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "no delay",
+			content:  "url=/next",
+			expected: []collector.Link{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			doc := `<meta http-equiv="refresh" content="` + tc.content + `">`
+
+			actual, err := collector.NewHTMLLinkCollector().GetLinks(strings.NewReader(doc))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
 }
@@ -12,18 +12,18 @@ var _ LinkCollector = (*JSONLinkCollector)(nil)
 // JSONLinkCollector is a collector that collects links from a reader of a text document.
 //
 //    c := NewJSONLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
+//    fmt.Println(doc.Links)
 type JSONLinkCollector struct{}
 
 // GetLinks collects links from a reader of an HTML document.
-func (t JSONLinkCollector) GetLinks(r io.Reader) ([]string, error) {
+func (t JSONLinkCollector) GetLinks(r io.Reader) (*Document, error) {
 	dec := json.NewDecoder(r)
-	links := make([]string, 0, initialLinksCapacity)
+	links := make([]Link, 0, initialLinksCapacity)
 
 	for {
 		token, err := dec.Token()
@@ -36,26 +36,26 @@ func (t JSONLinkCollector) GetLinks(r io.Reader) ([]string, error) {
 		}
 
 		if s, ok := token.(string); ok {
-			links = append(links, httpLinkRegexp.FindAllString(s, -1)...)
+			links = appendLinks(links, httpLinkRegexp.FindAllString(s, -1))
 		}
 	}
 
 	// Reduce memory allocation. GC will clean up the old links slice.
-	result := make([]string, len(links))
+	result := make([]Link, len(links))
 	copy(result, links)
 
-	return result, nil
+	return &Document{Links: result}, nil
 }
 
 // NewJSONLinkCollector creates a new collector for collecting links from a text document.
 //
 //    c := NewJSONLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
+//    fmt.Println(doc.Links)
 func NewJSONLinkCollector() *JSONLinkCollector {
 	return &JSONLinkCollector{}
 }
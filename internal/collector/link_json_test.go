@@ -42,20 +42,20 @@ func TestJSONLinkCollector_GetLinks_Success(t *testing.T) {
 	actual, err := c.GetLinks(f)
 	require.NoError(t, err, "could not get links")
 
-	expected := []string{
-		"http://google.com",
-		"http://www.google.com",
-		"https://google.com",
-		"https://www.google.com",
-		"http://www.bing.com",
-		"https://google.com/?q=hello+world",
-		"https://bing.com/?q=link+in+key",
-		"https://google.com/?q=array+element+1",
-		"https://bing.com/?q=array+element+2",
-		"https://google.com/?q=long+text+1",
-		"https://bing.com/?q=long+text+2",
-		"https://ran-dom.com/",
+	expected := []collector.Link{
+		{URL: "http://google.com"},
+		{URL: "http://www.google.com"},
+		{URL: "https://google.com"},
+		{URL: "https://www.google.com"},
+		{URL: "http://www.bing.com"},
+		{URL: "https://google.com/?q=hello+world"},
+		{URL: "https://bing.com/?q=link+in+key"},
+		{URL: "https://google.com/?q=array+element+1"},
+		{URL: "https://bing.com/?q=array+element+2"},
+		{URL: "https://google.com/?q=long+text+1"},
+		{URL: "https://bing.com/?q=long+text+2"},
+		{URL: "https://ran-dom.com/"},
 	}
 
-	assert.Equal(t, expected, actual)
+	assert.Equal(t, expected, actual.Links)
 }
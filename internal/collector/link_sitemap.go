@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var _ LinkCollector = (*SitemapLinkCollector)(nil)
+
+// SitemapLinkCollector is a collector that collects links from a reader of a sitemap.
+//
+//	c := NewSitemapLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+//
+// See https://www.sitemaps.org/protocol.html.
+type SitemapLinkCollector struct{}
+
+// GetLinks collects the <loc> of the <url> entries of a <urlset>, and the <loc> of the <sitemap> entries of a <sitemapindex>. The latter are the nested
+// sitemaps, so their links have the LinkTypeSitemap type.
+//
+// The other <loc> elements, such as the <image:loc> of the image sitemaps, are not collected. The sitemap is decoded according to the encoding of its XML
+// declaration.
+func (c SitemapLinkCollector) GetLinks(r io.Reader) (*Document, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.CharsetReader = charset.NewReaderLabel
+
+	locs := sitemapLocs{links: make([]Link, 0, initialLinksCapacity)}
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("could not collect links from sitemap: %w", err)
+		}
+
+		locs.read(token)
+	}
+
+	// Reduce memory allocation. GC will clean up the old links slice.
+	result := make([]Link, len(locs.links))
+	copy(result, locs.links)
+
+	return &Document{Links: result}, nil
+}
+
+// sitemapLocs collects the <loc> links of a sitemap, token by token.
+type sitemapLocs struct {
+	links []Link
+	// path is the local names of the open elements.
+	path []string
+	// loc is the link of the <loc> element that is being read, nil if there is none.
+	loc  *Link
+	text strings.Builder
+}
+
+// read reads a token of the sitemap.
+func (s *sitemapLocs) read(token xml.Token) {
+	switch t := token.(type) {
+	case xml.StartElement:
+		if t.Name.Local == "loc" && len(s.path) > 0 {
+			switch s.path[len(s.path)-1] {
+			case "url":
+				s.loc = &Link{}
+
+			case "sitemap":
+				s.loc = &Link{Type: LinkTypeSitemap}
+			}
+
+			s.text.Reset()
+		}
+
+		s.path = append(s.path, t.Name.Local)
+
+	case xml.CharData:
+		if s.loc != nil {
+			s.text.Write(t)
+		}
+
+	case xml.EndElement:
+		if len(s.path) > 0 {
+			s.path = s.path[:len(s.path)-1]
+		}
+
+		if s.loc == nil || t.Name.Local != "loc" {
+			return
+		}
+
+		if s.loc.URL = strings.TrimSpace(s.text.String()); s.loc.URL != "" {
+			s.links = append(s.links, *s.loc)
+		}
+
+		s.loc = nil
+	}
+}
+
+// isSitemapRoot checks whether the element is the root of a sitemap or a sitemap index.
+func isSitemapRoot(el xml.StartElement) bool {
+	return el.Name.Local == "urlset" || el.Name.Local == "sitemapindex"
+}
+
+// NewSitemapLinkCollector creates a new collector for collecting links from a sitemap.
+//
+//	c := NewSitemapLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+func NewSitemapLinkCollector() *SitemapLinkCollector {
+	return &SitemapLinkCollector{}
+}
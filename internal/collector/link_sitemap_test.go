@@ -0,0 +1,113 @@
+//go:build !testsignal
+
+package collector_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+)
+
+func TestSitemapLinkCollector_GetLinks_Error(t *testing.T) {
+	t.Parallel()
+
+	c := collector.NewSitemapLinkCollector()
+
+	actual, err := c.GetLinks(newErrorReader(errors.New("random error")))
+
+	assert.EqualError(t, err, "could not collect links from sitemap: random error")
+	assert.Nil(t, actual)
+}
+
+func TestSitemapLinkCollector_GetLinks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		doc      string
+		expected []collector.Link
+	}{
+		{
+			scenario: "empty",
+			doc:      `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></urlset>`,
+			expected: []collector.Link{},
+		},
+		{
+			scenario: "urlset",
+			doc: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+	<url>
+		<loc>
+			https://example.com/
+		</loc>
+		<lastmod>2022-12-01</lastmod>
+	</url>
+	<url>
+		<loc>https://example.com/posts?id=1&amp;page=2</loc>
+		<image:image>
+			<image:loc>https://example.com/image.png</image:loc>
+		</image:image>
+	</url>
+	<url>
+		<loc></loc>
+	</url>
+</urlset>`,
+			expected: []collector.Link{
+				{URL: "https://example.com/"},
+				{URL: "https://example.com/posts?id=1&page=2"},
+			},
+		},
+		{
+			scenario: "sitemap index",
+			doc: `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap>
+		<loc>https://example.com/sitemap-posts.xml</loc>
+	</sitemap>
+	<sitemap>
+		<loc>https://example.com/sitemap-pages.xml.gz</loc>
+		<lastmod>2022-12-01</lastmod>
+	</sitemap>
+</sitemapindex>`,
+			expected: []collector.Link{
+				{URL: "https://example.com/sitemap-posts.xml", Type: collector.LinkTypeSitemap},
+				{URL: "https://example.com/sitemap-pages.xml.gz", Type: collector.LinkTypeSitemap},
+			},
+		},
+		{
+			scenario: "loc outside of url",
+			doc:      `<urlset><loc>https://example.com/</loc></urlset>`,
+			expected: []collector.Link{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			c := collector.NewSitemapLinkCollector()
+
+			actual, err := c.GetLinks(strings.NewReader(tc.doc))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
+}
+
+func TestSitemapLinkCollector_GetLinks_Truncated(t *testing.T) {
+	t.Parallel()
+
+	c := collector.NewSitemapLinkCollector()
+
+	actual, err := c.GetLinks(strings.NewReader(`<urlset><url><loc>https://example.com/`))
+
+	assert.EqualError(t, err, "could not collect links from sitemap: XML syntax error on line 1: unexpected EOF")
+	assert.Nil(t, actual)
+}
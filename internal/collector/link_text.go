@@ -2,6 +2,7 @@ package collector
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"regexp"
@@ -13,24 +14,49 @@ var _ LinkCollector = (*TextLinkCollector)(nil)
 // Ref: https://mathiasbynens.be/demo/url-regex
 var httpLinkRegexp = regexp.MustCompile(`(https?)://(-\.)?([^\s/?.#]+\.?)+(/\S*)?`)
 
+const (
+	// defaultMaxLineSize is the default maximum size of a line of a text document.
+	defaultMaxLineSize = bufio.MaxScanTokenSize
+	// initialLineBufferSize is the initial size of the buffer for reading the lines of a text document.
+	initialLineBufferSize = 4096
+)
+
 // TextLinkCollector is a collector that collects links from a reader of a text document.
 //
 //    c := NewTextLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
-type TextLinkCollector struct{}
+//    fmt.Println(doc.Links)
+type TextLinkCollector struct {
+	maxLineSize int
+}
 
-// GetLinks collects links from a reader of an HTML document.
-func (t TextLinkCollector) GetLinks(r io.Reader) ([]string, error) {
+// GetLinks collects links from a reader of a text document.
+//
+// The document is read line by line. A line that is longer than the maximum line size, see WithMaxLineSize, is split at its last white space, so that the
+// document is read with a bounded buffer and the links are not broken.
+func (t TextLinkCollector) GetLinks(r io.Reader) (*Document, error) {
 	s := bufio.NewScanner(r)
-	links := make([]string, 0, initialLinksCapacity)
+	links := make([]Link, 0, initialLinksCapacity)
+
+	maxLineSize := t.maxLineSize
+	if maxLineSize < 1 {
+		maxLineSize = defaultMaxLineSize
+	}
+
+	bufSize := initialLineBufferSize
+	if bufSize > maxLineSize {
+		bufSize = maxLineSize
+	}
+
+	s.Buffer(make([]byte, 0, bufSize), maxLineSize)
+	s.Split(splitLines(maxLineSize))
 
 	for s.Scan() {
-		links = append(links, httpLinkRegexp.FindAllString(s.Text(), -1)...)
+		links = appendLinks(links, httpLinkRegexp.FindAllString(s.Text(), -1))
 	}
 
 	if err := s.Err(); err != nil {
@@ -38,21 +64,68 @@ func (t TextLinkCollector) GetLinks(r io.Reader) ([]string, error) {
 	}
 
 	// Reduce memory allocation. GC will clean up the old links slice.
-	result := make([]string, len(links))
+	result := make([]Link, len(links))
 	copy(result, links)
 
-	return result, nil
+	return &Document{Links: result}, nil
+}
+
+// splitLines returns a bufio.SplitFunc that splits the lines like bufio.ScanLines, but splits a line at its last white space when it is longer than the
+// maximum line size, instead of failing with bufio.ErrTooLong.
+func splitLines(maxLineSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance > 0 || token != nil || err != nil || len(data) < maxLineSize {
+			return advance, token, err
+		}
+
+		if i := bytes.LastIndexAny(data, " \t\f\v"); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+
+		return len(data), data, nil
+	}
 }
 
 // NewTextLinkCollector creates a new collector for collecting links from a text document.
 //
 //    c := NewTextLinkCollector()
-//    links, err := c.GetLinks(r)
+//    doc, err := c.GetLinks(r)
 //    if err != nil {
 //    	return nil, err
 //    }
 //
-//    fmt.Println(links)
-func NewTextLinkCollector() *TextLinkCollector {
-	return &TextLinkCollector{}
+//    fmt.Println(doc.Links)
+func NewTextLinkCollector(opts ...TextLinkCollectorOption) *TextLinkCollector {
+	c := &TextLinkCollector{
+		maxLineSize: defaultMaxLineSize,
+	}
+
+	for _, opt := range opts {
+		opt.applyTextLinkCollectorOption(c)
+	}
+
+	return c
+}
+
+// TextLinkCollectorOption is option to set up TextLinkCollector.
+type TextLinkCollectorOption interface {
+	applyTextLinkCollectorOption(c *TextLinkCollector)
+}
+
+type textLinkCollectorOptionFunc func(c *TextLinkCollector)
+
+func (f textLinkCollectorOptionFunc) applyTextLinkCollectorOption(c *TextLinkCollector) {
+	f(c)
+}
+
+// WithMaxLineSize sets the maximum size of a line of TextLinkCollector, which is the size of its read buffer. Zero means the default value, which is 64KB.
+//
+//	c := crawler.NewHTTPLinkCrawler(
+//		crawler.WithLinkCollector(collector.NewTextLinkCollector(collector.WithMaxLineSize(1024*1024)), "text/plain"),
+//	)
+func WithMaxLineSize(size int) TextLinkCollectorOption {
+	return textLinkCollectorOptionFunc(func(c *TextLinkCollector) {
+		c.maxLineSize = size
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,17 +41,81 @@ func TestTextLinkCollector_GetLinks_Success(t *testing.T) {
 	actual, err := c.GetLinks(f)
 	require.NoError(t, err, "could not get links")
 
-	expected := []string{
-		"http://google.com",
-		"http://www.google.com",
-		"https://google.com",
-		"https://www.google.com",
-		"http://www.bing.com",
-		"https://google.com/?q=long+text+1",
-		"https://bing.com/?q=long+text+2",
-		"http://127.0.0.1:8888/path",
-		"https://ran-dom.com/",
+	expected := []collector.Link{
+		{URL: "http://google.com"},
+		{URL: "http://www.google.com"},
+		{URL: "https://google.com"},
+		{URL: "https://www.google.com"},
+		{URL: "http://www.bing.com"},
+		{URL: "https://google.com/?q=long+text+1"},
+		{URL: "https://bing.com/?q=long+text+2"},
+		{URL: "http://127.0.0.1:8888/path"},
+		{URL: "https://ran-dom.com/"},
 	}
 
-	assert.Equal(t, expected, actual)
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestTextLinkCollector_GetLinks_LongLine(t *testing.T) {
+	t.Parallel()
+
+	filler := strings.Repeat("lorem ", 20_000)
+	doc := "https://example.com/first " + filler + "https://example.com/middle " + filler + "https://example.com/last\nhttps://example.com/next-line"
+
+	testCases := []struct {
+		scenario string
+		opts     []collector.TextLinkCollectorOption
+	}{
+		{
+			scenario: "default line size",
+		},
+		{
+			scenario: "small line size",
+			opts:     []collector.TextLinkCollectorOption{collector.WithMaxLineSize(100)},
+		},
+		{
+			scenario: "large line size",
+			opts:     []collector.TextLinkCollectorOption{collector.WithMaxLineSize(1024 * 1024)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			c := collector.NewTextLinkCollector(tc.opts...)
+
+			actual, err := c.GetLinks(strings.NewReader(doc))
+			require.NoError(t, err)
+
+			expected := []collector.Link{
+				{URL: "https://example.com/first"},
+				{URL: "https://example.com/middle"},
+				{URL: "https://example.com/last"},
+				{URL: "https://example.com/next-line"},
+			}
+
+			assert.Equal(t, expected, actual.Links)
+		})
+	}
+}
+
+func TestTextLinkCollector_GetLinks_LongWord(t *testing.T) {
+	t.Parallel()
+
+	// The word is longer than the maximum line size, so it is split without a white space.
+	doc := "https://example.com/" + strings.Repeat("a", 200) + " https://example.com/next"
+
+	c := collector.NewTextLinkCollector(collector.WithMaxLineSize(100))
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	expected := []collector.Link{
+		{URL: "https://example.com/" + strings.Repeat("a", 80)},
+		{URL: "https://example.com/next"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
 }
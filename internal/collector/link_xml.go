@@ -0,0 +1,235 @@
+package collector
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var _ LinkCollector = (*XMLLinkCollector)(nil)
+
+// XMLLinkCollector is a collector that collects links from a reader of an XML document.
+//
+// By default, it reads the links of the RSS and Atom feeds, and the <loc> of the sitemaps. In the generic mode, see WithGenericXML, it reads the links
+// that start with `http://` or `https://` in the text and the attributes of any XML document.
+//
+//	c := NewXMLLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+type XMLLinkCollector struct {
+	generic bool
+}
+
+// GetLinks collects links from a reader of an XML document.
+//
+// In the feed mode, the links are the text of the <link> and <guid isPermaLink="true"> elements, the href of the <link> elements, such as
+// <atom:link href>, and the url of the <enclosure> elements. The enclosures are the media of the feed items, so their links have the LinkTypeEnclosure
+// type, and so do the Atom <link rel="enclosure">. If the root is a <urlset> or a <sitemapindex>, the document is a sitemap, and the links are collected
+// the same way as SitemapLinkCollector does.
+//
+// The document is decoded according to the encoding of its XML declaration.
+func (c XMLLinkCollector) GetLinks(r io.Reader) (*Document, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+
+	// The feeds in the wild are not always well-formed, for example: they use the HTML entities without declaring them.
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	links := make([]Link, 0, initialLinksCapacity)
+
+	var (
+		// text is the text of the element whose text is a link, nil if there is none.
+		text *strings.Builder
+		// sitemap reads the document if it is a sitemap, nil if it is not.
+		sitemap *sitemapLocs
+		// root is true until the root element is read.
+		root = true
+	)
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("could not collect links from xml doc: %w", err)
+		}
+
+		if c.generic {
+			links = appendGenericXMLLinks(links, token)
+
+			continue
+		}
+
+		if el, ok := token.(xml.StartElement); ok && root {
+			root = false
+
+			if isSitemapRoot(el) {
+				sitemap = &sitemapLocs{links: links}
+			}
+		}
+
+		if sitemap != nil {
+			sitemap.read(token)
+
+			continue
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var l Link
+
+			l, text = feedLink(t)
+
+			if l.URL != "" {
+				links = append(links, l)
+			}
+
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+
+		case xml.EndElement:
+			if text == nil {
+				continue
+			}
+
+			if u := strings.TrimSpace(text.String()); u != "" {
+				links = append(links, Link{URL: u})
+			}
+
+			text = nil
+		}
+	}
+
+	if sitemap != nil {
+		links = sitemap.links
+	}
+
+	// Reduce memory allocation. GC will clean up the old links slice.
+	result := make([]Link, len(links))
+	copy(result, links)
+
+	return &Document{Links: result}, nil
+}
+
+// feedLink returns the link of an element of a feed. If the link is the text of the element, it returns a builder for reading the text instead.
+func feedLink(el xml.StartElement) (Link, *strings.Builder) {
+	switch el.Name.Local {
+	case "link":
+		href, ok := xmlAttrValue(el, "href")
+		if !ok {
+			// The <link> of RSS.
+			return Link{}, new(strings.Builder)
+		}
+
+		// The <link> of Atom.
+		rel, _ := xmlAttrValue(el, "rel")
+		l := Link{URL: strings.TrimSpace(href), Rel: strings.Fields(strings.ToLower(rel))}
+
+		if len(l.Rel) == 0 {
+			l.Rel = nil
+		}
+
+		if l.HasRel("enclosure") {
+			l.Type = LinkTypeEnclosure
+		}
+
+		return l, nil
+
+	case "enclosure":
+		u, _ := xmlAttrValue(el, "url")
+
+		return Link{URL: strings.TrimSpace(u), Type: LinkTypeEnclosure}, nil
+
+	case "guid":
+		// The guid is a permalink unless it says otherwise.
+		if isPermaLink, ok := xmlAttrValue(el, "isPermaLink"); ok && strings.TrimSpace(isPermaLink) != "true" {
+			return Link{}, nil
+		}
+
+		return Link{}, new(strings.Builder)
+	}
+
+	return Link{}, nil
+}
+
+// appendGenericXMLLinks appends the links that start with `http://` or `https://` in the text or the attributes of the token.
+func appendGenericXMLLinks(links []Link, token xml.Token) []Link {
+	switch t := token.(type) {
+	case xml.StartElement:
+		for _, attr := range t.Attr {
+			links = appendLinks(links, httpLinkRegexp.FindAllString(attr.Value, -1))
+		}
+
+	case xml.CharData:
+		links = appendLinks(links, httpLinkRegexp.FindAllString(string(t), -1))
+	}
+
+	return links
+}
+
+// xmlAttrValue returns the value of the first attribute of the element with the local name, no matter its namespace.
+func xmlAttrValue(el xml.StartElement, name string) (string, bool) {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// NewXMLLinkCollector creates a new collector for collecting links from an XML document. By default, it reads the links of the RSS and Atom feeds, and
+// the <loc> of the sitemaps, use WithGenericXML for the other documents.
+//
+//	c := NewXMLLinkCollector()
+//	doc, err := c.GetLinks(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	fmt.Println(doc.Links)
+func NewXMLLinkCollector(opts ...XMLLinkCollectorOption) *XMLLinkCollector {
+	c := &XMLLinkCollector{}
+
+	for _, opt := range opts {
+		opt.applyXMLLinkCollectorOption(c)
+	}
+
+	return c
+}
+
+// XMLLinkCollectorOption is option to set up XMLLinkCollector.
+type XMLLinkCollectorOption interface {
+	applyXMLLinkCollectorOption(c *XMLLinkCollector)
+}
+
+type xmlLinkCollectorOptionFunc func(c *XMLLinkCollector)
+
+func (f xmlLinkCollectorOptionFunc) applyXMLLinkCollectorOption(c *XMLLinkCollector) {
+	f(c)
+}
+
+// WithGenericXML turns on the generic mode of XMLLinkCollector. In this mode, the collector reads the links that start with `http://` or `https://` in
+// the text and the attributes of any XML document, instead of the links of the feeds.
+//
+//	c := crawler.NewHTTPLinkCrawler(
+//		crawler.WithLinkCollector(collector.NewXMLLinkCollector(collector.WithGenericXML()), "application/xml"),
+//	)
+func WithGenericXML() XMLLinkCollectorOption {
+	return xmlLinkCollectorOptionFunc(func(c *XMLLinkCollector) {
+		c.generic = true
+	})
+}
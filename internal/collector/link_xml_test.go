@@ -0,0 +1,174 @@
+//go:build !testsignal
+
+package collector_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+)
+
+const (
+	sampleRSS  = "../../resources/fixtures/sample.rss"
+	sampleAtom = "../../resources/fixtures/sample.atom"
+)
+
+func TestXMLLinkCollector_GetLinks_Error(t *testing.T) {
+	t.Parallel()
+
+	c := collector.NewXMLLinkCollector()
+
+	actual, err := c.GetLinks(newErrorReader(errors.New("random error")))
+
+	assert.EqualError(t, err, "could not collect links from xml doc: random error")
+	assert.Nil(t, actual)
+}
+
+func TestXMLLinkCollector_GetLinks_Feed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		file     string
+		expected []collector.Link
+	}{
+		{
+			scenario: "rss",
+			file:     sampleRSS,
+			expected: []collector.Link{
+				{URL: "https://example.com/"},
+				{URL: "https://example.com/feed.xml", Rel: []string{"self"}},
+				{URL: "https://example.com/posts/first"},
+				{URL: "https://example.com/?p=1"},
+				{URL: "https://cdn.example.com/first.mp3", Type: collector.LinkTypeEnclosure},
+				{URL: "/posts/second"},
+				{URL: "https://example.com/?p=3"},
+			},
+		},
+		{
+			scenario: "atom",
+			file:     sampleAtom,
+			expected: []collector.Link{
+				{URL: "https://example.com/"},
+				{URL: "https://example.com/atom.xml", Rel: []string{"self"}},
+				{URL: "https://example.com/posts/first", Rel: []string{"alternate"}},
+				{URL: "https://cdn.example.com/first.mp3", Type: collector.LinkTypeEnclosure, Rel: []string{"enclosure"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := os.Open(filepath.Clean(tc.file))
+			require.NoError(t, err, "could not open xml fixture")
+
+			defer f.Close() // nolint: errcheck,gosec
+
+			actual, err := collector.NewXMLLinkCollector().GetLinks(f)
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
+}
+
+func TestXMLLinkCollector_GetLinks_Sitemap(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		doc      string
+		expected []collector.Link
+	}{
+		{
+			scenario: "urlset",
+			doc: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url>
+		<loc>https://example.com/</loc>
+	</url>
+	<url>
+		<loc>https://example.com/posts?id=1&amp;page=2</loc>
+		<link>https://example.com/not-a-loc</link>
+	</url>
+</urlset>`,
+			expected: []collector.Link{
+				{URL: "https://example.com/"},
+				{URL: "https://example.com/posts?id=1&page=2"},
+			},
+		},
+		{
+			scenario: "sitemapindex",
+			doc: `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap>
+		<loc>https://example.com/sitemap-posts.xml</loc>
+	</sitemap>
+</sitemapindex>`,
+			expected: []collector.Link{
+				{URL: "https://example.com/sitemap-posts.xml", Type: collector.LinkTypeSitemap},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := collector.NewXMLLinkCollector().GetLinks(strings.NewReader(tc.doc))
+			require.NoError(t, err, "could not get links")
+
+			assert.Equal(t, tc.expected, actual.Links)
+		})
+	}
+}
+
+func TestXMLLinkCollector_GetLinks_Generic(t *testing.T) {
+	t.Parallel()
+
+	doc := `<?xml version="1.0"?>
+		<catalog source="https://example.com/catalog.xml">
+			<book isbn="123">
+				<homepage>https://example.com/books/123</homepage>
+				<note>Mirrors: http://mirror1.example.org/123 and https://mirror2.example.org/123</note>
+				<link>/books/123</link>
+			</book>
+		</catalog>
+	`
+
+	c := collector.NewXMLLinkCollector(collector.WithGenericXML())
+
+	actual, err := c.GetLinks(strings.NewReader(doc))
+	require.NoError(t, err, "could not get links")
+
+	expected := []collector.Link{
+		{URL: "https://example.com/catalog.xml"},
+		{URL: "https://example.com/books/123"},
+		{URL: "http://mirror1.example.org/123"},
+		{URL: "https://mirror2.example.org/123"},
+	}
+
+	assert.Equal(t, expected, actual.Links)
+}
+
+func TestXMLLinkCollector_GetLinks_Truncated(t *testing.T) {
+	t.Parallel()
+
+	doc := `<rss><channel><link>https://example.com/</link><item><link>https://example.com/post</link>`
+
+	actual, err := collector.NewXMLLinkCollector().GetLinks(strings.NewReader(doc))
+
+	assert.EqualError(t, err, "could not collect links from xml doc: XML syntax error on line 1: unexpected EOF")
+	assert.Nil(t, actual)
+}
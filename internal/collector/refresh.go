@@ -0,0 +1,69 @@
+package collector
+
+import "strings"
+
+// parseMetaRefresh parses the content of a <meta http-equiv="refresh"> and returns the url to refresh to. It returns false if the content does not have
+// an url, for example: `5` refreshes the page itself.
+//
+// For example: `0; url=https://example.com/` returns `https://example.com/`.
+//
+// See https://html.spec.whatwg.org/multipage/semantics.html#shared-declarative-refresh-steps.
+func parseMetaRefresh(content string) (string, bool) {
+	s := strings.TrimLeft(content, htmlSpaces)
+
+	// The delay, such as `0` or `1.5`.
+	delay := strings.TrimLeft(s, "0123456789")
+	if delay == s && !strings.HasPrefix(s, ".") {
+		return "", false
+	}
+
+	s = strings.TrimLeft(delay, "0123456789.")
+
+	if s != "" && !strings.ContainsAny(s[:1], ";,"+htmlSpaces) {
+		return "", false
+	}
+
+	s = strings.TrimLeft(s, htmlSpaces)
+
+	if s != "" && (s[0] == ';' || s[0] == ',') {
+		s = strings.TrimLeft(s[1:], htmlSpaces)
+	}
+
+	if s == "" {
+		return "", false
+	}
+
+	if s = skipRefreshURLPrefix(s); s == "" {
+		return "", false
+	}
+
+	// The url could be quoted.
+	if s[0] == '\'' || s[0] == '"' {
+		quote := s[0]
+		s = s[1:]
+
+		if i := strings.IndexByte(s, quote); i >= 0 {
+			s = s[:i]
+		}
+	}
+
+	s = strings.TrimRight(s, htmlSpaces)
+
+	return s, s != ""
+}
+
+// skipRefreshURLPrefix skips the `url=` prefix of the url in a refresh content, case-insensitively. The content is returned as is if it does not have the
+// prefix.
+func skipRefreshURLPrefix(s string) string {
+	if len(s) < 3 || !strings.EqualFold(s[:3], "url") { // nolint: gomnd // The length of "url".
+		return s
+	}
+
+	rest := strings.TrimLeft(s[3:], htmlSpaces)
+
+	if !strings.HasPrefix(rest, "=") {
+		return s
+	}
+
+	return strings.TrimLeft(rest[1:], htmlSpaces)
+}
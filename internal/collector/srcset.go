@@ -0,0 +1,88 @@
+package collector
+
+import "strings"
+
+// srcsetAttributes are the attributes that contain a list of image candidates instead of a single url.
+var srcsetAttributes = map[string]struct{}{
+	"srcset":      {},
+	"imagesrcset": {},
+}
+
+// isSrcsetAttribute checks whether the attribute contains a list of image candidates.
+func isSrcsetAttribute(attr string) bool {
+	_, ok := srcsetAttributes[attr]
+
+	return ok
+}
+
+// parseSrcset parses a srcset attribute and returns the urls of the image candidates, in order.
+//
+// The candidates are split by the candidate-string grammar of the HTML spec. The descriptors, such as `1x` or `100w`, are skipped without validation
+// because only the urls are needed.
+//
+// For example: `a.jpg 1x, b.jpg 2x` returns `a.jpg` and `b.jpg`.
+//
+// See https://html.spec.whatwg.org/multipage/images.html#parse-a-srcset-attribute.
+func parseSrcset(s string) []string {
+	var urls []string
+
+	pos := 0
+
+	for {
+		// Skip the whitespaces and the commas before the url.
+		for pos < len(s) && (isHTMLSpace(s[pos]) || s[pos] == ',') {
+			pos++
+		}
+
+		if pos >= len(s) {
+			return urls
+		}
+
+		start := pos
+
+		for pos < len(s) && !isHTMLSpace(s[pos]) {
+			pos++
+		}
+
+		u := s[start:pos]
+
+		// A url that ends with commas does not have descriptors.
+		if strings.HasSuffix(u, ",") {
+			urls = append(urls, strings.TrimRight(u, ","))
+
+			continue
+		}
+
+		urls = append(urls, u)
+		pos = skipSrcsetDescriptors(s, pos)
+	}
+}
+
+// skipSrcsetDescriptors skips the descriptors of an image candidate, and returns the position after the comma that ends the candidate. The commas in
+// parentheses do not end the candidate.
+func skipSrcsetDescriptors(s string, pos int) int {
+	inParens := false
+
+	for ; pos < len(s); pos++ {
+		switch c := s[pos]; {
+		case inParens:
+			inParens = c != ')'
+
+		case c == '(':
+			inParens = true
+
+		case c == ',':
+			return pos + 1
+		}
+	}
+
+	return pos
+}
+
+// htmlSpaces are the ASCII whitespaces as defined by the HTML spec.
+const htmlSpaces = " \t\n\f\r"
+
+// isHTMLSpace checks whether the character is an ASCII whitespace as defined by the HTML spec.
+func isHTMLSpace(c byte) bool {
+	return strings.IndexByte(htmlSpaces, c) >= 0
+}
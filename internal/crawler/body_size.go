@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// ErrBodyTooLarge indicates that the response body is larger than the maximum body size.
+	ErrBodyTooLarge = Error("response body too large")
+)
+
+// limitedBody is the body of a response that could be read up to a maximum size. When there is more to read, the body either ends, if it is truncated, or
+// fails with ErrBodyTooLarge.
+type limitedBody struct {
+	io.ReadCloser
+
+	maxSize   int64
+	remaining int64
+	truncate  bool
+	exceeded  bool
+}
+
+// Read reads the body up to the maximum size.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, b.checkExceeded()
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err // nolint: wrapcheck // The error is from the original body.
+}
+
+// checkExceeded reads a byte past the maximum size to tell whether the body is larger than the maximum size, so a body of exactly the maximum size is
+// neither truncated nor too large.
+func (b *limitedBody) checkExceeded() error {
+	if !b.exceeded {
+		var buf [1]byte
+
+		if _, err := io.ReadFull(b.ReadCloser, buf[:]); err != nil {
+			return err // nolint: wrapcheck // The error is from the original body.
+		}
+
+		b.exceeded = true
+	}
+
+	if b.truncate {
+		return io.EOF
+	}
+
+	return bodyTooLargeError(b.maxSize)
+}
+
+// bodyTooLargeError returns an ErrBodyTooLarge error with the maximum size.
+func bodyTooLargeError(maxSize int64) error {
+	return fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, maxSize)
+}
+
+// isTruncated checks whether the body is truncated at the maximum size. It is false for a nil body, which means the body is not limited.
+func (b *limitedBody) isTruncated() bool {
+	return b != nil && b.truncate && b.exceeded
+}
+
+// isTooLarge checks whether the body is larger than the maximum size and is not truncated. It is false for a nil body, which means the body is not limited.
+func (b *limitedBody) isTooLarge() bool {
+	return b != nil && !b.truncate && b.exceeded
+}
+
+// limitBody limits the body of the response to the maximum body size of the crawler. It returns nil if the body size is not limited.
+func (c HTTPLinkCrawler) limitBody(resp *http.Response) *limitedBody {
+	if c.maxBodySize <= 0 {
+		return nil
+	}
+
+	b := &limitedBody{
+		ReadCloser: resp.Body,
+		maxSize:    c.maxBodySize,
+		remaining:  c.maxBodySize,
+		truncate:   c.truncateBody,
+	}
+
+	resp.Body = b
+
+	return b
+}
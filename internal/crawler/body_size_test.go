@@ -0,0 +1,164 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_MaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	// Every anchor is 18 bytes.
+	const body = `<a href="/a">A</a><a href="/b">B</a>`
+
+	testCases := []struct {
+		scenario        string
+		mockServer      func(s *httpmock.Server)
+		maxBodySize     int64
+		truncate        bool
+		expectedLinks   []string
+		expectedError   string
+		expectedErrorIs error
+		truncated       bool
+	}{
+		{
+			scenario: "unlimited",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(body)
+			},
+			expectedLinks: []string{"/a", "/b"},
+		},
+		{
+			scenario: "body is smaller than the limit",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(body)
+			},
+			maxBodySize:   1024,
+			expectedLinks: []string{"/a", "/b"},
+		},
+		{
+			scenario: "body is exactly the limit",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(body)
+			},
+			maxBodySize:   36,
+			expectedLinks: []string{"/a", "/b"},
+		},
+		{
+			scenario: "body is too large",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(body)
+			},
+			maxBodySize:     18,
+			expectedError:   "response body too large: more than 18 bytes",
+			expectedErrorIs: crawler.ErrBodyTooLarge,
+		},
+		{
+			scenario: "body is truncated",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(body)
+			},
+			maxBodySize:   18,
+			truncate:      true,
+			expectedLinks: []string{"/a"},
+			truncated:     true,
+		},
+		{
+			scenario: "decoded body is truncated",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					ReturnHeader("Content-Encoding", "gzip").
+					Run(gzipped(body))
+			},
+			maxBodySize:   18,
+			truncate:      true,
+			expectedLinks: []string{"/a"},
+			truncated:     true,
+		},
+		{
+			scenario: "truncated json could not be parsed",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "application/json").
+					Return(`{"links": ["https://example.com/a", "https://example.com/b"]}`)
+			},
+			maxBodySize:     40,
+			truncate:        true,
+			expectedError:   "failed to get links: could not collect links from json doc: unexpected EOF",
+			expectedErrorIs: io.ErrUnexpectedEOF,
+			truncated:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(tc.mockServer)(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithLinkCollector(collector.NewJSONLinkCollector(), "application/json"),
+				crawler.WithMaxBodySize(tc.maxBodySize, tc.truncate),
+			)
+
+			source := srv.URL() + samplePath
+			results := c.CrawLinks(context.Background(), sendLinks(source))
+
+			ctx, cancel := contextWithDeadline(t, time.Second)
+			defer cancel()
+
+			var actual crawler.LinkCrawlerResult
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("test timed out")
+
+			case actual = <-results:
+			}
+
+			assert.Equal(t, tc.truncated, actual.Truncated)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, actual.Error, tc.expectedError)
+				assert.ErrorIs(t, actual.Error, tc.expectedErrorIs)
+
+				return
+			}
+
+			require.NoError(t, actual.Error)
+
+			expected := make([]collector.Link, 0, len(tc.expectedLinks))
+
+			for _, l := range tc.expectedLinks {
+				expected = append(expected, anchor(srv.URL()+l, strings.ToUpper(l[1:])))
+			}
+
+			assert.Equal(t, expected, actual.InternalLinks)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const (
+	// charsetSniffLen is the number of bytes to look for the byte order mark and the <meta charset> of an HTML document.
+	//
+	// See https://html.spec.whatwg.org/multipage/parsing.html#prescan-a-byte-stream-to-determine-its-encoding.
+	charsetSniffLen = 1024
+
+	// defaultCharset is the charset of the documents that do not declare one.
+	defaultCharset = "utf-8"
+)
+
+// transcodeBody replaces the body of the response with a reader that transcodes it to UTF-8, so that the collectors always get UTF-8 documents.
+//
+// The charset is determined by the byte order mark, then the `charset` parameter of the Content-Type, then the <meta charset> of an HTML document. The
+// documents without a charset are UTF-8. The XML documents are left as is because they declare their encoding, which is decoded by the collectors.
+//
+// The charset is detected from the bytes that could be read, the read error is kept by the buffer and returned to the collector.
+func (c HTTPLinkCrawler) transcodeBody(ctx context.Context, resp *http.Response, contentType string) {
+	if isXMLMediaType(contentType) {
+		return
+	}
+
+	br := bufio.NewReaderSize(resp.Body, charsetSniffLen)
+	sniff, _ := br.Peek(charsetSniffLen) // nolint: errcheck // The error is returned by the next read.
+
+	enc, name := c.detectCharset(ctx, sniff, resp.Header.Get("Content-Type"), contentType)
+
+	c.log.Debug(ctx, "detected charset", "http.charset", name)
+
+	// The byte order mark is removed, and it overrides the detected charset.
+	resp.Body = decodedBody{
+		Reader: transform.NewReader(br, unicode.BOMOverride(enc.NewDecoder())),
+		Closer: resp.Body,
+	}
+}
+
+// detectCharset detects the charset of a document from its first bytes, its Content-Type header and its media type. It returns the encoding and the name
+// of the charset. The UTF-8 documents are not transcoded, so the encoding of the UTF-8 charset is encoding.Nop.
+func (c HTTPLinkCrawler) detectCharset(ctx context.Context, sniff []byte, contentTypeHeader, contentType string) (encoding.Encoding, string) {
+	labels := make([]string, 0, 2)
+
+	if _, params, err := mime.ParseMediaType(contentTypeHeader); err == nil && params["charset"] != "" {
+		labels = append(labels, params["charset"])
+	}
+
+	if contentType == "text/html" {
+		if label := metaCharset(sniff); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	for _, label := range labels {
+		enc, name := charset.Lookup(label)
+		if enc == nil {
+			c.log.Debug(ctx, "unsupported charset, skip", "http.charset", label)
+
+			continue
+		}
+
+		if name == defaultCharset {
+			break
+		}
+
+		return enc, name
+	}
+
+	return encoding.Nop, defaultCharset
+}
+
+// metaCharset returns the charset of the <meta charset> or the <meta http-equiv="Content-Type" content> of an HTML document, if there is one in its first
+// bytes.
+func metaCharset(sniff []byte) string {
+	z := html.NewTokenizer(bytes.NewReader(sniff))
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if name, hasAttr := z.TagName(); string(name) != "meta" || !hasAttr {
+				continue
+			}
+
+			if label := metaTagCharset(z); label != "" {
+				return label
+			}
+		}
+	}
+}
+
+// metaTagCharset returns the charset of the attributes of a <meta> tag.
+func metaTagCharset(z *html.Tokenizer) string {
+	var httpEquiv, content string
+
+	for {
+		key, val, more := z.TagAttr()
+
+		switch string(key) {
+		case "charset":
+			return strings.TrimSpace(string(val))
+
+		case "http-equiv":
+			httpEquiv = string(val)
+
+		case "content":
+			content = string(val)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if !strings.EqualFold(httpEquiv, "content-type") {
+		return ""
+	}
+
+	if _, params, err := mime.ParseMediaType(content); err == nil {
+		return params["charset"]
+	}
+
+	return ""
+}
+
+// isXMLMediaType checks whether the media type is an XML document, such as `application/xml` or `application/rss+xml`.
+func isXMLMediaType(contentType string) bool {
+	return strings.HasSuffix(contentType, "/xml") || strings.HasSuffix(contentType, "+xml")
+}
@@ -0,0 +1,122 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_Charset(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		contentType   string
+		body          func(*http.Request) ([]byte, error)
+		expectedLinks []collector.Link
+	}{
+		{
+			scenario:      "utf-8 by default",
+			contentType:   "text/html",
+			body:          encoded(encoding.Nop, `<a href="https://example.com/">Привет, мир</a>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "Привет, мир")},
+		},
+		{
+			scenario:      "charset of content type",
+			contentType:   "text/html; charset=windows-1251",
+			body:          encoded(charmap.Windows1251, `<a href="https://example.com/">Привет, мир</a>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "Привет, мир")},
+		},
+		{
+			scenario:      "meta charset",
+			contentType:   "text/html",
+			body:          encoded(japanese.ShiftJIS, `<html><head><meta charset="Shift_JIS"></head><body><a href="https://example.com/">こんにちは</a></body></html>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "こんにちは")},
+		},
+		{
+			scenario:      "meta http-equiv",
+			contentType:   "",
+			body:          encoded(charmap.ISO8859_5, `<html><head><meta http-equiv="Content-Type" content="text/html; charset=iso-8859-5"></head><a href="https://example.com/">Привет</a>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "Привет")},
+		},
+		{
+			scenario:      "charset of content type overrides meta charset",
+			contentType:   "text/html; charset=windows-1251",
+			body:          encoded(charmap.Windows1251, `<meta charset="iso-8859-5"><a href="https://example.com/">Привет</a>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "Привет")},
+		},
+		{
+			scenario:      "unknown charset of content type",
+			contentType:   "text/html; charset=unknown",
+			body:          encoded(charmap.Windows1251, `<meta charset="windows-1251"><a href="https://example.com/">Привет</a>`),
+			expectedLinks: []collector.Link{anchor("https://example.com/", "Привет")},
+		},
+		{
+			scenario:      "byte order mark overrides charset of content type",
+			contentType:   "text/plain; charset=iso-8859-1",
+			body:          encoded(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), "Visit https://example.com/ now"),
+			expectedLinks: []collector.Link{{URL: "https://example.com/"}},
+		},
+		{
+			scenario:      "utf-8 byte order mark",
+			contentType:   "application/json",
+			body:          encoded(unicode.UTF8BOM, `{"url": "https://example.com/"}`),
+			expectedLinks: []collector.Link{{URL: "https://example.com/"}},
+		},
+		{
+			scenario:      "xml encoding declaration",
+			contentType:   "application/rss+xml",
+			body:          encoded(charmap.Windows1251, `<?xml version="1.0" encoding="windows-1251"?><rss><channel><title>Новости</title><link>https://example.com/</link></channel></rss>`),
+			expectedLinks: []collector.Link{{URL: "https://example.com/"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", tc.contentType).
+					Run(tc.body)
+			})(t)
+
+			c := crawler.NewHTTPLinkCrawler(crawler.WithLinkCollectors(map[string]collector.LinkCollector{
+				"text/html":           collector.NewHTMLLinkCollector(),
+				"text/plain":          collector.NewTextLinkCollector(),
+				"application/json":    collector.NewJSONLinkCollector(),
+				"application/rss+xml": collector.NewXMLLinkCollector(),
+			}))
+
+			source := srv.URL() + samplePath
+			results := c.CrawLinks(context.Background(), sendLinks(source))
+
+			assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+				Source:        source,
+				InternalLinks: []collector.Link{},
+				ExternalLinks: tc.expectedLinks,
+				Attempts:      1,
+			})
+		})
+	}
+}
+
+// encoded returns an httpmock handler that responds the body in the encoding.
+func encoded(enc encoding.Encoding, body string) func(*http.Request) ([]byte, error) {
+	return func(*http.Request) ([]byte, error) {
+		return enc.NewEncoder().Bytes([]byte(body)) // nolint: wrapcheck
+	}
+}
@@ -0,0 +1,91 @@
+package crawler
+
+import (
+	"net/http"
+)
+
+// Middleware wraps the http.RoundTripper of HTTPLinkCrawler, for example, to trace, to log or to sign the requests.
+//
+//	func logRequests(next http.RoundTripper) http.RoundTripper {
+//		return crawler.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			log.Printf("%s %s", req.Method, req.URL)
+//
+//			return next.RoundTrip(req)
+//		})
+//	}
+//
+//	c := crawler.NewHTTPLinkCrawler(crawler.WithMiddlewares(logRequests))
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+var _ http.RoundTripper = RoundTripperFunc(nil)
+
+// RoundTripperFunc is an adapter to use a function as an http.RoundTripper, for the middlewares and the test doubles.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newClient builds the http client of HTTPLinkCrawler.
+//
+// The client is a copy of the one of WithHTTPClient, so the original client is never modified. Then the settings of the crawler are composed with the
+// ones of the client:
+//   - The transport of WithTransport replaces the one of the client. The proxy of WithProxy is set on a copy of the transport if it is an http.Transport,
+//     and the middlewares wrap the transport, the first one is the outermost.
+//   - The timeout of WithClientTimeout overrides the one of the client. If both are zero, the timeout is defaultTimeout.
+//   - The cookie jar of WithCookieJar overrides the one of the client.
+//   - The redirect policy of the crawler runs first, then the CheckRedirect of the client, if any.
+func (c HTTPLinkCrawler) newClient() *http.Client {
+	client := &http.Client{}
+
+	if c.baseClient != nil {
+		*client = *c.baseClient
+	}
+
+	if c.transport != nil {
+		client.Transport = c.transport
+	}
+
+	if c.proxy != nil || c.noProxy != nil {
+		client.Transport = proxyTransport(client.Transport, c.proxy, c.noProxy)
+	}
+
+	if len(c.middlewares) > 0 {
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+
+		for i := len(c.middlewares) - 1; i >= 0; i-- {
+			transport = c.middlewares[i](transport)
+		}
+
+		client.Transport = transport
+	}
+
+	if c.timeout != 0 {
+		client.Timeout = c.timeout
+	} else if client.Timeout == 0 {
+		client.Timeout = defaultTimeout
+	}
+
+	if c.jar != nil {
+		client.Jar = c.jar
+	}
+
+	checkClientRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := c.authorizeRedirect(req, via); err != nil {
+			return err
+		}
+
+		if checkClientRedirect != nil {
+			return checkClientRedirect(req, via)
+		}
+
+		return nil
+	}
+
+	return client
+}
@@ -0,0 +1,243 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_Transport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, call)
+	}
+
+	middleware := func(name string) crawler.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return crawler.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				record(name)
+
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	transport := crawler.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		record("transport " + req.URL.String())
+
+		return htmlResponse(req, `<a href="/path2">Path 2</a>`), nil
+	})
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithHTTPClient(&http.Client{Transport: http.DefaultTransport}),
+		crawler.WithTransport(transport),
+		crawler.WithMiddlewares(middleware("first"), middleware("second")),
+		crawler.WithMiddlewares(middleware("third")),
+	)
+
+	source := "https://crawler.test" + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor("https://crawler.test/path2", "Path 2")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+
+	assert.Equal(t, []string{"first", "second", "third", "transport " + source}, calls)
+}
+
+func TestLinkCrawler_CrawLinks_HTTPClient_Redirect(t *testing.T) {
+	t.Parallel()
+
+	errForbidden := errors.New("forbidden redirect")
+
+	client := &http.Client{
+		Transport: crawler.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == samplePath {
+				resp := htmlResponse(req, "")
+				resp.StatusCode = http.StatusFound
+				resp.Header.Set("Location", "/forbidden")
+
+				return resp, nil
+			}
+
+			return htmlResponse(req, `<a href="/path2">Path 2</a>`), nil
+		}),
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			if req.URL.Path == "/forbidden" {
+				return errForbidden
+			}
+
+			return nil
+		},
+	}
+
+	testCases := []struct {
+		scenario      string
+		options       []crawler.HTTPLinkCrawlerOption
+		expectedError string
+	}{
+		{
+			scenario:      "redirect policy of the client",
+			expectedError: "forbidden redirect",
+		},
+		{
+			scenario:      "redirect policy of the crawler runs first",
+			options:       []crawler.HTTPLinkCrawlerOption{crawler.WithMaxRedirects(0)},
+			expectedError: "stopped after 0 redirects",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			c := crawler.NewHTTPLinkCrawler(append([]crawler.HTTPLinkCrawlerOption{
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithHTTPClient(client),
+			}, tc.options...)...)
+
+			// The client is not modified.
+			assert.Zero(t, client.Timeout)
+			assert.Nil(t, client.Jar)
+
+			results := c.CrawLinks(context.Background(), sendLinks("https://crawler.test"+samplePath))
+
+			ctx, cancel := contextWithDeadline(t, time.Second)
+			defer cancel()
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("test timed out")
+
+			case r := <-results:
+				assert.ErrorContains(t, r.Error, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_HTTPClient_Timeout(t *testing.T) {
+	t.Parallel()
+
+	// The transport responds when the request is canceled.
+	transport := crawler.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+
+		return nil, req.Context().Err()
+	})
+
+	testCases := []struct {
+		scenario string
+		client   *http.Client
+		options  []crawler.HTTPLinkCrawlerOption
+	}{
+		{
+			scenario: "timeout of the client",
+			client:   &http.Client{Transport: transport, Timeout: 10 * time.Millisecond},
+		},
+		{
+			scenario: "timeout of the crawler overrides the client",
+			client:   &http.Client{Transport: transport, Timeout: time.Hour},
+			options:  []crawler.HTTPLinkCrawlerOption{crawler.WithClientTimeout(10 * time.Millisecond)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// The timeout of the crawler is set before the client, the order of the options does not matter.
+			c := crawler.NewHTTPLinkCrawler(append(tc.options,
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithHTTPClient(tc.client),
+			)...)
+
+			results := c.CrawLinks(context.Background(), sendLinks("https://crawler.test"+samplePath))
+
+			ctx, cancel := contextWithDeadline(t, time.Second)
+			defer cancel()
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("test timed out")
+
+			case r := <-results:
+				var netErr net.Error
+
+				require.ErrorAs(t, r.Error, &netErr)
+				assert.True(t, netErr.Timeout())
+			}
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_HTTPClient_Proxy(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/path2">Path 2</a>`)
+	})(t)
+
+	p := newTestProxy(hostOf(t, srv.URL()))
+	transport := &http.Transport{}
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithHTTPClient(&http.Client{Transport: transport}),
+		crawler.WithProxy(p.httpProxy(t)),
+	)
+
+	source := "http://" + proxiedHost + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor("http://"+proxiedHost+"/path2", "Path 2")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+
+	assert.Equal(t, []string{"GET " + source}, p.requests())
+	assert.Nil(t, transport.Proxy, "the transport of the client is not modified")
+}
+
+func htmlResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// ErrInvalidCookieFile indicates that the cookie file is not in the Netscape format.
+	ErrInvalidCookieFile = Error("invalid cookie file")
+)
+
+const (
+	// netscapeCookieFields is the number of fields of a cookie in the Netscape format.
+	netscapeCookieFields = 7
+	// httpOnlyPrefix is the prefix of the domain of the HttpOnly cookies, as written by curl.
+	httpOnlyPrefix = "#HttpOnly_"
+)
+
+// NewNetscapeCookieJar creates a cookie jar with the cookies of a Netscape cookie file, the cookies.txt format of curl, wget and the browser extensions.
+//
+// Every line is a cookie of 7 fields that are separated by tabs: the domain, whether the subdomains are included (TRUE or FALSE), the path, whether the
+// cookie is secure (TRUE or FALSE), the expiration in unix seconds (0 for a session cookie), the name and the value. The empty lines and the comments are
+// skipped. The domain of an HttpOnly cookie is prefixed with `#HttpOnly_`.
+//
+//	f, err := os.Open("cookies.txt")
+//	if err != nil {
+//		return err
+//	}
+//
+//	defer f.Close()
+//
+//	jar, err := crawler.NewNetscapeCookieJar(f)
+//	if err != nil {
+//		return err
+//	}
+//
+//	c := crawler.NewHTTPLinkCrawler(crawler.WithCookieJar(jar))
+//
+// See https://curl.se/docs/http-cookies.html.
+func NewNetscapeCookieJar(r io.Reader) (http.CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		// This should not happen because cookiejar.New never fails.
+		return nil, fmt.Errorf("could not create cookie jar: %w", err)
+	}
+
+	s := bufio.NewScanner(r)
+
+	for line := 1; s.Scan(); line++ {
+		u, cookie, err := parseNetscapeCookie(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidCookieFile, line, err.Error())
+		}
+
+		if cookie != nil {
+			jar.SetCookies(u, []*http.Cookie{cookie})
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read cookie file: %w", err)
+	}
+
+	return jar, nil
+}
+
+// parseNetscapeCookie parses a line of a Netscape cookie file. It returns the url that the cookie is set for, and a nil cookie if the line is empty or a
+// comment.
+//
+// nolint: goerr113 // The error is wrapped by the caller.
+func parseNetscapeCookie(line string) (*url.URL, *http.Cookie, error) {
+	httpOnly := strings.HasPrefix(line, httpOnlyPrefix)
+	if httpOnly {
+		line = line[len(httpOnlyPrefix):]
+	}
+
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return nil, nil, nil
+	}
+
+	fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+	if len(fields) != netscapeCookieFields {
+		return nil, nil, fmt.Errorf("expected %d fields separated by tabs, got %d", netscapeCookieFields, len(fields))
+	}
+
+	domain, includeSubdomains, path, secure, expires, name, value := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6]
+
+	if domain == "" || name == "" {
+		return nil, nil, fmt.Errorf("missing domain or name")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expiration %q", expires)
+	}
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Secure:   strings.EqualFold(secure, "TRUE"),
+		HttpOnly: httpOnly,
+	}
+
+	// The cookie of a host without the subdomains has no domain attribute.
+	if strings.EqualFold(includeSubdomains, "TRUE") {
+		cookie.Domain = domain
+	}
+
+	if expiresAt > 0 {
+		cookie.Expires = time.Unix(expiresAt, 0)
+	}
+
+	u := &url.URL{Scheme: "http", Host: strings.TrimPrefix(domain, "."), Path: path}
+
+	if cookie.Secure {
+		u.Scheme = "https"
+	}
+
+	return u, cookie, nil
+}
@@ -0,0 +1,97 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_CookieJar(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Run(echoHeaders("Cookie"))
+	})(t)
+
+	jar, err := crawler.NewNetscapeCookieJar(strings.NewReader(strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		"127.0.0.1\tFALSE\t/\tFALSE\t0\tsession\tabc",
+		"#HttpOnly_127.0.0.1\tFALSE\t/path\tFALSE\t4102444800\tremember\tme",
+		"127.0.0.1\tFALSE\t/other\tFALSE\t0\tother\tpath",
+		"127.0.0.1\tFALSE\t/\tFALSE\t1\texpired\tcookie",
+		"example.com\tTRUE\t/\tFALSE\t0\tother\thost",
+	}, "\n")))
+	require.NoError(t, err)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithCookieJar(jar),
+	)
+
+	source := srv.URL() + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor(srv.URL()+"/Cookie", "remember=me; session=abc")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+}
+
+func TestNewNetscapeCookieJar_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		file     string
+		expected string
+	}{
+		{
+			scenario: "not enough fields",
+			file:     "# comment\nexample.com\tFALSE\t/\tFALSE\t0\tname",
+			expected: "invalid cookie file: line 2: expected 7 fields separated by tabs, got 6",
+		},
+		{
+			scenario: "separated by spaces",
+			file:     "example.com FALSE / FALSE 0 name value",
+			expected: "invalid cookie file: line 1: expected 7 fields separated by tabs, got 1",
+		},
+		{
+			scenario: "missing name",
+			file:     "example.com\tFALSE\t/\tFALSE\t0\t\tvalue",
+			expected: "invalid cookie file: line 1: missing domain or name",
+		},
+		{
+			scenario: "invalid expiration",
+			file:     "example.com\tFALSE\t/\tFALSE\tnever\tname\tvalue",
+			expected: `invalid cookie file: line 1: invalid expiration "never"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			jar, err := crawler.NewNetscapeCookieJar(strings.NewReader(tc.file))
+
+			assert.Nil(t, jar)
+			assert.ErrorIs(t, err, crawler.ErrInvalidCookieFile)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/nhatthm/go-playground-20221201/internal/brotli"
+)
+
+const (
+	// ErrUnsupportedContentEncoding indicates that the content encoding of the response could not be decoded.
+	ErrUnsupportedContentEncoding = Error("unsupported content encoding")
+)
+
+const (
+	// acceptEncoding is the content encodings that the crawler could decode.
+	acceptEncoding = "gzip, deflate, br"
+)
+
+// decodeBody replaces the body of the response with a reader that decodes its Content-Encoding, so that the collectors always get the decoded content.
+//
+// The encodings are decoded in the reverse order of their application, and the decoders are created on the first read, so an empty body, such as the one of
+// a HEAD request, is not an error. The Content-Encoding and the Content-Length headers are removed because they do not describe the decoded body anymore.
+//
+// See https://www.rfc-editor.org/rfc/rfc9110#section-8.4.
+func decodeBody(resp *http.Response) error {
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	body := io.Reader(resp.Body)
+	decoded := false
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+
+		newReader, ok := decoders[encoding]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupportedContentEncoding, encoding)
+		}
+
+		body = &lazyReader{src: body, newReader: newReader}
+		decoded = true
+	}
+
+	if !decoded {
+		return nil
+	}
+
+	resp.Body = decodedBody{Reader: body, Closer: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// decoders is the decoders of the supported content encodings.
+var decoders = map[string]func(io.Reader) (io.Reader, error){
+	"gzip":    newGzipReader,
+	"x-gzip":  newGzipReader,
+	"deflate": newDeflateReader,
+	"br":      newBrotliReader,
+}
+
+func newGzipReader(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r) // nolint: wrapcheck // The error is wrapped by the collectors.
+}
+
+// newDeflateReader creates a reader of the deflate encoding. The encoding is a zlib stream, but some servers send a raw deflate stream, so the zlib header is
+// checked before reading.
+//
+// See https://www.rfc-editor.org/rfc/rfc9110#section-8.4.1.2.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+		return zlib.NewReader(br) // nolint: wrapcheck // The error is wrapped by the collectors.
+	}
+
+	return flate.NewReader(br), nil
+}
+
+func newBrotliReader(r io.Reader) (io.Reader, error) {
+	return brotli.NewReader(r), nil
+}
+
+// isZlibHeader checks whether the bytes are a zlib header with the deflate compression method.
+//
+// See https://www.rfc-editor.org/rfc/rfc1950#section-2.2.
+func isZlibHeader(header []byte) bool {
+	cmf, flg := header[0], header[1]
+
+	return cmf&0x0f == 8 && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
+
+// lazyReader is an io.Reader that creates the reader of its source on the first read.
+type lazyReader struct {
+	src       io.Reader
+	newReader func(io.Reader) (io.Reader, error)
+
+	r   io.Reader
+	err error
+}
+
+// Read creates the reader if it is not created yet, and reads from it.
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.r == nil && l.err == nil {
+		l.r, l.err = l.newReader(l.src)
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	return l.r.Read(p) // nolint: wrapcheck // The error is from the decoder.
+}
+
+// decodedBody is the decoded body of a response, it closes the original body.
+type decodedBody struct {
+	io.Reader
+	io.Closer
+}
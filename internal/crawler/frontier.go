@@ -0,0 +1,171 @@
+package crawler
+
+import (
+	"context"
+
+	"github.com/bool64/ctxd"
+)
+
+// crawlTask is a source to be crawled by a worker.
+type crawlTask struct {
+	source string
+	index  int // The position in the input sources, -1 if the source is not from the input.
+	parent string
+	depth  int
+	// check is used to check the health of the source instead of crawling it.
+	check bool
+	// skip is used to send a skipped result for an input source that is not crawled, so that every input source has a result.
+	skip bool
+}
+
+// crawlFeedback is sent by a worker when it finishes a task, with the internal links that were found in the source and the links to be checked.
+type crawlFeedback struct {
+	task   crawlTask
+	links  []string
+	checks []string
+}
+
+// frontier keeps track of the sources to be crawled.
+//
+// When the recursive mode is on (maxDepth > 0), the internal links found in a source are put back into the frontier, and the sources that have been visited
+// are skipped. Otherwise, the frontier only forwards the input sources to the workers.
+type frontier struct {
+	log ctxd.Logger
+
+	// maxDepth is the maximum number of hops from the input sources. Zero means the recursive mode is off.
+	maxDepth int
+	// maxPages is the maximum number of pages to crawl. Zero means unlimited.
+	maxPages int
+
+	queue    []crawlTask
+	visited  map[string]struct{}
+	numPages int
+	pending  int
+}
+
+// run dispatches the tasks to the workers until the sources are exhausted and all the dispatched tasks are done, or the context is canceled.
+//
+// The input sources are only read when there is no discovered link waiting in the queue. This keeps the memory bounded by the buffer of the publisher.
+//
+// Every input source has a result: when an input source is visited or over the page budget, a skip task is queued instead, so that the results can be
+// reordered by the indexes of the input sources without waiting for an index that never comes.
+func (f *frontier) run(ctx context.Context, sources <-chan Source, feedbacks <-chan crawlFeedback) <-chan crawlTask {
+	tasks := make(chan crawlTask)
+
+	go func() {
+		defer close(tasks)
+
+		for {
+			if sources == nil && f.pending == 0 && len(f.queue) == 0 {
+				return
+			}
+
+			var (
+				out  chan<- crawlTask
+				in   <-chan Source
+				next crawlTask
+			)
+
+			if len(f.queue) > 0 {
+				out, next = tasks, f.queue[0]
+			} else {
+				in = sources
+			}
+
+			select {
+			// Operation canceled.
+			case <-ctx.Done():
+				return
+
+			case source, ok := <-in:
+				if !ok {
+					sources = nil
+
+					continue
+				}
+
+				task := crawlTask{source: source.URL, index: source.Index}
+
+				if !f.enqueue(ctx, task) {
+					task.skip = true
+					f.queue = append(f.queue, task)
+				}
+
+			case out <- next:
+				f.queue = f.queue[1:]
+				f.pending++
+
+			case fb := <-feedbacks:
+				f.pending--
+
+				// The checks do not count in the page budget, and they are deduplicated by the workers.
+				for _, link := range fb.checks {
+					f.queue = append(f.queue, crawlTask{source: link, index: -1, check: true})
+				}
+
+				if fb.task.depth >= f.maxDepth {
+					continue
+				}
+
+				for _, link := range fb.links {
+					f.enqueue(ctx, crawlTask{source: link, index: -1, parent: fb.task.source, depth: fb.task.depth + 1})
+				}
+			}
+		}
+	}()
+
+	return tasks
+}
+
+// enqueue puts the task into the queue if it is not visited and the budget is not exhausted. It returns false if the task is skipped.
+func (f *frontier) enqueue(ctx context.Context, task crawlTask) bool {
+	if f.maxPages > 0 && f.numPages >= f.maxPages {
+		f.log.Debug(ctx, "page budget exhausted, skipped source", "crawler.http.source", task.source)
+
+		return false
+	}
+
+	if f.maxDepth > 0 {
+		key := visitKey(task.source)
+
+		if _, ok := f.visited[key]; ok {
+			return false
+		}
+
+		f.visited[key] = struct{}{}
+	}
+
+	f.numPages++
+	f.queue = append(f.queue, task)
+
+	return true
+}
+
+// newFrontier creates a new frontier.
+func newFrontier(maxDepth, maxPages int, log ctxd.Logger) *frontier {
+	return &frontier{
+		log:      log,
+		maxDepth: maxDepth,
+		maxPages: maxPages,
+		visited:  make(map[string]struct{}),
+	}
+}
+
+// visitKey returns the key to identify a visited source.
+//
+// The fragment is removed because it does not change the requested resource, and an empty path is the same as the root path.
+func visitKey(source string) string {
+	u, err := parseURL(source)
+	if err != nil {
+		return source
+	}
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return u.String()
+}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// setHeaders sets the headers of a request: the user agent, the custom headers, and the credentials of its host. The custom headers override the user
+// agent.
+func (c HTTPLinkCrawler) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", c.userAgent)
+
+	for name, values := range c.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+
+	c.authorize(req)
+}
+
+// authorize sets the Authorization header of a request if there are credentials for its host. When the crawler has credentials, the Authorization header
+// of the other hosts is removed, so the credentials are never sent to another host, even after a redirect.
+func (c HTTPLinkCrawler) authorize(req *http.Request) {
+	if len(c.credentials) == 0 {
+		return
+	}
+
+	req.Header.Del("Authorization")
+
+	if auth, ok := c.credentials.lookup(req.URL.Host); ok {
+		req.Header.Set("Authorization", auth)
+	}
+}
+
+// authorizeRedirect is the http.Client.CheckRedirect of HTTPLinkCrawler. It applies the redirect policy, see checkRedirect, and then authorizes the
+// redirect request for its own host.
+func (c HTTPLinkCrawler) authorizeRedirect(req *http.Request, via []*http.Request) error {
+	if err := checkRedirect(req, via); err != nil {
+		return err
+	}
+
+	c.authorize(req)
+
+	return nil
+}
+
+// hostCredentials is the Authorization header of the hosts. The key is the lower case host, with or without the port.
+type hostCredentials map[string]string
+
+// lookup returns the Authorization header of the host. The credentials of the host with the port win over the ones of the host without the port.
+func (h hostCredentials) lookup(host string) (string, bool) {
+	host = strings.ToLower(host)
+
+	if auth, ok := h[host]; ok {
+		return auth, true
+	}
+
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.HasSuffix(host, "]") {
+		auth, ok := h[host[:i]]
+
+		return auth, ok
+	}
+
+	return "", false
+}
+
+// set sets the Authorization header of the host.
+func (h hostCredentials) set(host, auth string) hostCredentials {
+	if h == nil {
+		h = make(hostCredentials)
+	}
+
+	h[strings.ToLower(host)] = auth
+
+	return h
+}
+
+// basicAuth returns the Authorization header of the basic authentication.
+//
+// See https://www.rfc-editor.org/rfc/rfc7617#section-2.
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
@@ -0,0 +1,168 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_Headers(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Run(echoHeaders("User-Agent", "X-Env", "Accept-Encoding"))
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithUserAgent("link-crawler/1.0"),
+		crawler.WithHeaders(http.Header{"x-env": {"staging"}}),
+	)
+
+	source := srv.URL() + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source: source,
+		InternalLinks: []collector.Link{
+			anchor(srv.URL()+"/User-Agent", "link-crawler/1.0"),
+			anchor(srv.URL()+"/X-Env", "staging"),
+			anchor(srv.URL()+"/Accept-Encoding", "gzip, deflate, br"),
+		},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+}
+
+func TestLinkCrawler_CrawLinks_Credentials(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario        string
+		option          func(host string) crawler.HTTPLinkCrawlerOption
+		expectedAuth    string
+		expectedAllHost bool
+	}{
+		{
+			scenario: "basic auth of host with port",
+			option: func(host string) crawler.HTTPLinkCrawlerOption {
+				return crawler.WithBasicAuth(host, "user", "pass")
+			},
+			expectedAuth: "Basic dXNlcjpwYXNz",
+		},
+		{
+			scenario: "bearer token of host with port",
+			option: func(host string) crawler.HTTPLinkCrawlerOption {
+				return crawler.WithBearerToken(strings.ToUpper(host), "token")
+			},
+			expectedAuth: "Bearer token",
+		},
+		{
+			scenario: "bearer token of host without port",
+			option: func(string) crawler.HTTPLinkCrawlerOption {
+				return crawler.WithBearerToken("127.0.0.1", "token")
+			},
+			expectedAuth:    "Bearer token",
+			expectedAllHost: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			other := httpmock.New(func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Run(echoHeaders("Authorization"))
+
+				s.ExpectGet("/redirected").
+					ReturnHeader("Content-Type", "text/html").
+					Run(echoHeaders("Authorization"))
+			})(t)
+
+			srv := httpmock.New(func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Run(echoHeaders("Authorization"))
+
+				s.ExpectGet("/redirect").
+					ReturnCode(httpmock.StatusFound).
+					ReturnHeader("Location", other.URL()+"/redirected")
+			})(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithNumWorkers(1),
+				tc.option(hostOf(t, srv.URL())),
+			)
+
+			results := c.CrawLinks(context.Background(), sendLinks(srv.URL()+samplePath, other.URL()+samplePath, srv.URL()+"/redirect"))
+
+			actual := make(map[string]string)
+
+			for i := 0; i < 3; i++ {
+				select {
+				case <-time.After(time.Second):
+					t.Fatal("test timed out")
+
+				case r := <-results:
+					require.NoError(t, r.Error)
+					require.Len(t, r.InternalLinks, 1)
+
+					actual[r.Source] = r.InternalLinks[0].Text
+				}
+			}
+
+			otherAuth := ""
+			if tc.expectedAllHost {
+				otherAuth = tc.expectedAuth
+			}
+
+			expected := map[string]string{
+				srv.URL() + samplePath:   tc.expectedAuth,
+				other.URL() + samplePath: otherAuth,
+				srv.URL() + "/redirect":  otherAuth,
+			}
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+// echoHeaders returns an httpmock handler that responds a link per header, the path of the link is the header name, and the text is the header value.
+func echoHeaders(names ...string) func(*http.Request) ([]byte, error) {
+	return func(r *http.Request) ([]byte, error) {
+		var sb strings.Builder
+
+		for _, name := range names {
+			sb.WriteString(`<a href="/` + name + `">` + strings.Join(r.Header.Values(name), ", ") + `</a>`)
+		}
+
+		return []byte(sb.String()), nil
+	}
+}
+
+func hostOf(t *testing.T, rawURL string) string {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	return u.Host
+}
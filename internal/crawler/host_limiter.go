@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// hostSlot limits the requests to a host.
+type hostSlot struct {
+	limiter *rate.Limiter // rate.Inf means no rate limit.
+	sem     chan struct{} // nil means no concurrency limit.
+}
+
+// hostLimiter limits the rate and the number of concurrent requests per host. It is safe for concurrent use.
+type hostLimiter struct {
+	// rps is the number of requests per second per host. Zero means unlimited.
+	rps float64
+	// burst is the maximum number of requests that could be sent at once to a host.
+	burst int
+	// concurrency is the maximum number of concurrent requests per host. Zero means unlimited.
+	concurrency int
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlot
+}
+
+// slot returns the slot of the host, the slot is created when the host is seen for the first time.
+//
+// If the crawl delay is slower than the rate of the host, the crawl delay wins, and the requests are sent one by one. The crawl delay of a host is not
+// known by every request, for example, the link checks do not read the robots.txt, so the rate of an existing slot is slowed down when a slower crawl delay
+// comes.
+func (l *hostLimiter) slot(host string, crawlDelay time.Duration) *hostSlot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, burst := l.limit(crawlDelay)
+
+	if s, ok := l.hosts[host]; ok {
+		if limit < s.limiter.Limit() {
+			s.limiter.SetLimit(limit)
+			s.limiter.SetBurst(burst)
+		}
+
+		return s
+	}
+
+	s := &hostSlot{limiter: rate.NewLimiter(limit, burst)}
+
+	if l.concurrency > 0 {
+		s.sem = make(chan struct{}, l.concurrency)
+	}
+
+	l.hosts[host] = s
+
+	return s
+}
+
+// limit returns the rate and the burst of a host with the crawl delay. It is rate.Inf if there is neither a rate limit nor a crawl delay.
+func (l *hostLimiter) limit(crawlDelay time.Duration) (rate.Limit, int) {
+	switch {
+	case crawlDelay > 0 && (l.rps <= 0 || rate.Every(crawlDelay) < rate.Limit(l.rps)):
+		return rate.Every(crawlDelay), 1
+
+	case l.rps > 0:
+		return rate.Limit(l.rps), l.burst
+	}
+
+	return rate.Inf, 1
+}
+
+// acquire waits until a request could be sent to the host. The caller must call the release function when the request is done.
+func (l *hostLimiter) acquire(ctx context.Context, host string, crawlDelay time.Duration) (func(), error) {
+	s := l.slot(host, crawlDelay)
+	release := func() {}
+
+	if s.sem != nil {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("could not acquire host slot: %w", ctx.Err())
+
+		case s.sem <- struct{}{}:
+		}
+
+		once := sync.Once{}
+		release = func() {
+			once.Do(func() { <-s.sem })
+		}
+	}
+
+	if err := s.limiter.Wait(ctx); err != nil {
+		release()
+
+		return nil, fmt.Errorf("could not wait for host rate limit: %w", err)
+	}
+
+	return release, nil
+}
+
+// newHostLimiter creates a new hostLimiter.
+func newHostLimiter(rps float64, burst, concurrency int) *hostLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &hostLimiter{
+		rps:         rps,
+		burst:       burst,
+		concurrency: concurrency,
+		hosts:       make(map[string]*hostSlot),
+	}
+}
+
+// releaseOnClose is an io.ReadCloser that calls the release function when it is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+
+	release func()
+}
+
+// Close closes the reader and calls the release function.
+func (r releaseOnClose) Close() error {
+	defer r.release()
+
+	return r.ReadCloser.Close() // nolint: wrapcheck // The error is from the original reader.
+}
@@ -0,0 +1,257 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+	"github.com/nhatthm/go-playground-20221201/internal/mockplanner"
+)
+
+func TestLinkCrawler_CrawLinks_HostConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const numSources = 4
+
+	var inFlight, maxInFlight int32
+
+	// The requests are counted in flight, no matter in which order they arrive.
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(mockplanner.Unordered())
+
+		for i := 0; i < numSources; i++ {
+			s.ExpectGet(fmt.Sprintf("/path%d", i+1)).
+				ReturnHeader("Content-Type", "text/html").
+				Run(func(*http.Request) ([]byte, error) {
+					n := atomic.AddInt32(&inFlight, 1)
+					defer atomic.AddInt32(&inFlight, -1)
+
+					for {
+						m := atomic.LoadInt32(&maxInFlight)
+						if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+							break
+						}
+					}
+
+					time.Sleep(20 * time.Millisecond)
+
+					return []byte(`<a href="/">Home</a>`), nil
+				})
+		}
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(numSources),
+		crawler.WithHostConcurrency(1),
+	)
+
+	sources := make([]string, numSources)
+
+	for i := range sources {
+		sources[i] = fmt.Sprintf("%s/path%d", srv.URL(), i+1)
+	}
+
+	for r := range c.CrawLinks(context.Background(), sendLinks(sources...)) {
+		assert.NoError(t, r.Error)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+}
+
+func TestLinkCrawler_CrawLinks_HostRateLimit(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		robots   string
+		options  []crawler.HTTPLinkCrawlerOption
+	}{
+		{
+			scenario: "rate limit",
+			options: []crawler.HTTPLinkCrawlerOption{
+				crawler.WithHostRateLimit(20, 1),
+			},
+		},
+		{
+			scenario: "crawl delay",
+			robots:   "User-agent: *\nCrawl-delay: 0.05\n",
+			options: []crawler.HTTPLinkCrawlerOption{
+				crawler.WithRobotsTxt(true),
+			},
+		},
+		{
+			scenario: "crawl delay is slower than rate limit",
+			robots:   "User-agent: *\nCrawl-delay: 0.05\n",
+			options: []crawler.HTTPLinkCrawlerOption{
+				crawler.WithRobotsTxt(true),
+				crawler.WithHostRateLimit(1000, 10),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu           sync.Mutex
+				requestTimes []time.Time
+			)
+
+			srv := httpmock.New(func(s *httpmock.Server) {
+				s.WithPlanner(mockplanner.Unordered())
+
+				if tc.robots != "" {
+					s.ExpectGet("/robots.txt").
+						Return(tc.robots)
+				}
+
+				for i := 1; i <= 3; i++ {
+					s.ExpectGet(fmt.Sprintf("/path%d", i)).
+						ReturnHeader("Content-Type", "text/html").
+						Run(func(*http.Request) ([]byte, error) {
+							mu.Lock()
+							defer mu.Unlock()
+
+							requestTimes = append(requestTimes, time.Now())
+
+							return []byte(`<a href="/">Home</a>`), nil
+						})
+				}
+			})(t)
+
+			opts := append([]crawler.HTTPLinkCrawlerOption{
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithNumWorkers(3),
+			}, tc.options...)
+
+			c := crawler.NewHTTPLinkCrawler(opts...)
+			startTime := time.Now()
+
+			for r := range c.CrawLinks(context.Background(), sendLinks(srv.URL()+"/path1", srv.URL()+"/path2", srv.URL()+"/path3")) {
+				assert.NoError(t, r.Error)
+			}
+
+			// The 1st request is sent immediately, the next 2 requests have to wait for 50ms each.
+			assert.GreaterOrEqual(t, time.Since(startTime), 100*time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			require.Len(t, requestTimes, 3)
+
+			sort.Slice(requestTimes, func(i, j int) bool {
+				return requestTimes[i].Before(requestTimes[j])
+			})
+
+			// The requests are sent 50ms apart, but a request that arrives late shortens the gap to the next one. Half of the interval is a safe margin
+			// that still tells the limited requests from the concurrent ones.
+			for i := 1; i < len(requestTimes); i++ {
+				assert.GreaterOrEqual(t, requestTimes[i].Sub(requestTimes[i-1]), 25*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_HostRateLimit_SlowerCrawlDelay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu           sync.Mutex
+		requestTimes []time.Time
+	)
+
+	checked := make(chan struct{})
+
+	delayedSrv := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(mockplanner.Unordered())
+
+		// The link check does not read the robots.txt, so it reaches the host without the crawl delay.
+		s.ExpectHead("/").
+			Run(func(*http.Request) ([]byte, error) {
+				close(checked)
+
+				return nil, nil
+			})
+
+		s.ExpectGet("/robots.txt").
+			Return("User-agent: *\nCrawl-delay: 0.05\n")
+
+		for i := 1; i <= 3; i++ {
+			s.ExpectGet(fmt.Sprintf("/path%d", i)).
+				ReturnHeader("Content-Type", "text/html").
+				Run(func(*http.Request) ([]byte, error) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					requestTimes = append(requestTimes, time.Now())
+
+					return []byte(`<a href="/">Home</a>`), nil
+				})
+		}
+	})(t)
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/robots.txt").
+			ReturnCode(httpmock.StatusNotFound)
+
+		s.ExpectGet("/page").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="` + delayedSrv.URL() + `/">External</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(3),
+		crawler.WithRobotsTxt(true),
+		crawler.WithLinkCheck(true),
+	)
+
+	// The pages of the host are sent after the link check has reached it.
+	sources := make(chan crawler.Source)
+
+	go func() {
+		defer close(sources)
+
+		sources <- crawler.Source{URL: srv.URL() + "/page"}
+
+		<-checked
+
+		for i := 1; i <= 3; i++ {
+			sources <- crawler.Source{URL: fmt.Sprintf("%s/path%d", delayedSrv.URL(), i), Index: i}
+		}
+	}()
+
+	for r := range c.CrawLinks(context.Background(), sources) {
+		assert.NoError(t, r.Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, requestTimes, 3)
+
+	sort.Slice(requestTimes, func(i, j int) bool {
+		return requestTimes[i].Before(requestTimes[j])
+	})
+
+	// The crawl delay of the robots.txt still applies to the host, see TestLinkCrawler_CrawLinks_HostRateLimit for the margin.
+	for i := 1; i < len(requestTimes); i++ {
+		assert.GreaterOrEqual(t, requestTimes[i].Sub(requestTimes[i-1]), 25*time.Millisecond)
+	}
+}
@@ -3,6 +3,8 @@ package crawler
 import (
 	"context"
 	"time"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
 )
 
 const (
@@ -20,15 +22,47 @@ const (
 	defaultTimeout = 30 * time.Second
 )
 
+// Source is an input source of LinkCrawler.
+type Source struct {
+	URL string
+
+	// Index is the position of the source in the input sources, starting from 0. It is passed as is to the LinkCrawlerResult of the source.
+	Index int
+}
+
 // LinkCrawlerResult is the result of LinkCrawler.
 type LinkCrawlerResult struct {
 	Source        string
-	InternalLinks []string
-	ExternalLinks []string
+	InternalLinks []collector.Link
+	ExternalLinks []collector.Link
 	Error         error
+
+	// Index is the position of the source in the input sources, starting from 0. It is -1 if the source is not from the input, for example: a discovered
+	// link in the recursive mode, or a checked link.
+	Index int
+	// Skipped is true if the input source is not crawled because it has been visited or the page budget is exhausted. The result has no other field than
+	// the Source and the Index, it only marks the position of the source so that every input source has a result.
+	Skipped bool
+
+	// Parent is the page that links to the source. It is empty if the source is from the input.
+	Parent string
+	// Depth is the number of hops from the input source to the source. It is 0 if the source is from the input.
+	Depth int
+	// Attempts is the number of requests sent to the source, including the retries. It is 0 if no request was sent.
+	Attempts int
+	// NoFollow is true if the source asks the crawlers not to follow its links, for example: <meta name="robots" content="nofollow">.
+	NoFollow bool
+	// Canonical is the absolute url of the <link rel="canonical"> of the source. It is empty if the source does not declare one.
+	Canonical string
+	// Redirects is the redirect chain of the last attempt, in order. It is empty if the source did not redirect.
+	Redirects []Redirect
+	// Truncated is true if the body of the source is larger than the maximum body size, and only the links of its first bytes are collected.
+	Truncated bool
+	// Check is the health check of the source when the result is for a checked link rather than a crawled page. It is nil for the crawled pages.
+	Check *LinkCheck
 }
 
 // LinkCrawler counts links from multiple sources.
 type LinkCrawler interface {
-	CrawLinks(ctx context.Context, sources <-chan string) <-chan LinkCrawlerResult
+	CrawLinks(ctx context.Context, sources <-chan Source) <-chan LinkCrawlerResult
 }
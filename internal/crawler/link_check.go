@@ -0,0 +1,195 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+// LinkCheck is the health check of a link, when the link checking mode is on.
+type LinkCheck struct {
+	// StatusCode is the status code of the link. It is 0 if there is no response.
+	StatusCode int
+	// Latency is the duration of the check, including the retries and the redirects.
+	Latency time.Duration
+	// Referrers are the pages that reference the link, in the order of discovery.
+	Referrers []string
+}
+
+// linkChecker keeps track of the links to be checked during a run. It is safe for concurrent use.
+//
+// Each link is checked only once, no matter how many pages reference it.
+type linkChecker struct {
+	mu    sync.Mutex
+	links map[string]*LinkCrawlerResult // Key is the visit key of the link.
+	order []string
+}
+
+// add records the referrer of the links, and returns the links that have not been seen before.
+func (l *linkChecker) add(referrer string, links []string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var newLinks []string
+
+	seen := make(map[string]struct{}, len(links))
+
+	for _, link := range links {
+		key := visitKey(link)
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		if r, ok := l.links[key]; ok {
+			r.Check.Referrers = append(r.Check.Referrers, referrer)
+
+			continue
+		}
+
+		l.links[key] = &LinkCrawlerResult{Source: key, Index: -1, Check: &LinkCheck{Referrers: []string{referrer}}}
+		l.order = append(l.order, key)
+
+		newLinks = append(newLinks, key)
+	}
+
+	return newLinks
+}
+
+// set records the result of a check.
+func (l *linkChecker) set(result LinkCrawlerResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.links[result.Source]
+	if !ok {
+		return
+	}
+
+	result.Check.Referrers = r.Check.Referrers
+	*r = result
+}
+
+// results returns the results of the checks, in the order of discovery.
+func (l *linkChecker) results() []LinkCrawlerResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	results := make([]LinkCrawlerResult, 0, len(l.order))
+
+	for _, key := range l.order {
+		r := *l.links[key]
+		check := *r.Check
+		check.Referrers = append([]string(nil), r.Check.Referrers...)
+		r.Check = &check
+
+		results = append(results, r)
+	}
+
+	return results
+}
+
+// newLinkChecker creates a new linkChecker.
+func newLinkChecker() *linkChecker {
+	return &linkChecker{
+		links: make(map[string]*LinkCrawlerResult),
+	}
+}
+
+// linksToCheck records the links of the result for checking, and returns the links that have not been checked before.
+//
+// The scheme-relative links, such as `//example.com/`, are resolved with the scheme of the source.
+func (c HTTPLinkCrawler) linksToCheck(checker *linkChecker, result LinkCrawlerResult) []string {
+	if !c.checkLinks || result.Error != nil {
+		return nil
+	}
+
+	scheme := "https"
+
+	if u, err := parseURL(result.Source); err == nil {
+		scheme = u.Scheme
+	}
+
+	links := make([]string, 0, len(result.InternalLinks)+len(result.ExternalLinks))
+
+	for _, link := range result.InternalLinks {
+		links = append(links, link.URL)
+	}
+
+	for _, link := range result.ExternalLinks {
+		u := link.URL
+
+		if strings.HasPrefix(u, "//") {
+			u = scheme + ":" + u
+		}
+
+		links = append(links, u)
+	}
+
+	return checker.add(result.Source, links)
+}
+
+// doCheck checks the health of a link by sending a HEAD request. If the HEAD request fails, it falls back to a GET request because some servers do not
+// support HEAD.
+//
+// The robots.txt is not checked because the body of the link is never read, but the per-host limits and the retry policy still apply.
+func (c HTTPLinkCrawler) doCheck(ctx context.Context, task crawlTask) LinkCrawlerResult {
+	ctx = ctxd.AddFields(ctx, "crawler.http.link", task.source)
+	result := LinkCrawlerResult{Source: task.source, Index: task.index, Check: &LinkCheck{}}
+
+	linkURL, err := parseURL(task.source)
+	if err != nil {
+		c.log.Error(ctx, "failed to parse link", "error", err)
+
+		result.Error = err
+
+		return result
+	}
+
+	ctx = ctxd.AddFields(ctx,
+		"http.url", linkURL.String(),
+		"http.timeout", c.client.Timeout.String(),
+	)
+
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		startTime := time.Now()
+		resp, trace, err := c.sendWithRetry(ctx, method, *linkURL, 0, c.redirectPolicy())
+
+		result.Check.Latency = time.Since(startTime)
+		result.Attempts += trace.attempts
+		result.Redirects = trace.redirects
+		result.Check.StatusCode, result.Error = 0, err
+
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				result.Error = ErrOperationCanceled
+
+				break
+			}
+
+			continue
+		}
+
+		_ = resp.Body.Close() // nolint: errcheck
+
+		result.Check.StatusCode = resp.StatusCode
+
+		if resp.StatusCode < http.StatusBadRequest {
+			break
+		}
+	}
+
+	c.log.Debug(ctx, "checked link",
+		"http.status_code", result.Check.StatusCode,
+		"http.duration", result.Check.Latency.String(),
+	)
+
+	return result
+}
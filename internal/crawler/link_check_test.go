@@ -0,0 +1,159 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_LinkCheck(t *testing.T) {
+	t.Parallel()
+
+	externalSrv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectHead("/").
+			ReturnCode(httpmock.StatusOK)
+	})(t)
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/ok">OK</a>
+				<a href="/broken">Broken</a>
+				<a href="/broken#anchor">Broken again</a>
+				<a href="` + externalSrv.URL() + `/">External</a>
+			`)
+
+		s.ExpectGet("/page2").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/broken">Broken</a>
+				<a href="/no-head">No HEAD</a>
+			`)
+
+		// Every link is checked only once.
+		s.ExpectHead("/ok").
+			ReturnCode(httpmock.StatusOK)
+
+		s.ExpectHead("/broken").
+			ReturnCode(httpmock.StatusNotFound)
+
+		s.ExpectGet("/broken").
+			ReturnCode(httpmock.StatusNotFound)
+
+		s.ExpectHead("/no-head").
+			ReturnCode(http.StatusMethodNotAllowed)
+
+		s.ExpectGet("/no-head").
+			ReturnCode(httpmock.StatusOK)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(1),
+		crawler.WithLinkCheck(true),
+	)
+
+	page1, page2 := srv.URL()+"/page1", srv.URL()+"/page2"
+	results := c.CrawLinks(context.Background(), sendLinks(page1, page2))
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	var pages, checks []crawler.LinkCrawlerResult
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("test timed out")
+
+		case r, ok := <-results:
+			if !ok {
+				assert.Len(t, pages, 2)
+
+				expected := map[string]struct {
+					statusCode int
+					attempts   int
+					referrers  []string
+				}{
+					srv.URL() + "/ok":       {statusCode: 200, attempts: 1, referrers: []string{page1}},
+					srv.URL() + "/broken":   {statusCode: 404, attempts: 2, referrers: []string{page1, page2}},
+					externalSrv.URL() + "/": {statusCode: 200, attempts: 1, referrers: []string{page1}},
+					srv.URL() + "/no-head":  {statusCode: 200, attempts: 2, referrers: []string{page2}},
+				}
+
+				assert.Len(t, checks, len(expected))
+
+				for _, check := range checks {
+					e, ok := expected[check.Source]
+					if !assert.True(t, ok, "unexpected check for %s", check.Source) {
+						continue
+					}
+
+					assert.NoError(t, check.Error)
+					assert.Equal(t, e.statusCode, check.Check.StatusCode, check.Source)
+					assert.Equal(t, e.attempts, check.Attempts, check.Source)
+					assert.Equal(t, e.referrers, check.Check.Referrers, check.Source)
+					assert.Positive(t, check.Check.Latency, check.Source)
+				}
+
+				return
+			}
+
+			if r.Check == nil {
+				assert.Empty(t, checks, "the checks are sent after the pages")
+
+				pages = append(pages, r)
+			} else {
+				checks = append(checks, r)
+			}
+		}
+	}
+}
+
+func TestLinkCrawler_CrawLinks_LinkCheck_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="http://localhost:0/">Unreachable</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithLinkCheck(true),
+	)
+
+	results := c.CrawLinks(context.Background(), sendLinks(srv.URL()))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        srv.URL(),
+		InternalLinks: []collector.Link{},
+		ExternalLinks: []collector.Link{anchor("http://localhost:0/", "Unreachable")},
+		Attempts:      1,
+	})
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Errorf("test timed out")
+
+	case actual := <-results:
+		assert.Equal(t, "http://localhost:0/", actual.Source)
+		assert.Equal(t, 0, actual.Check.StatusCode)
+		assert.Equal(t, []string{srv.URL()}, actual.Check.Referrers)
+		assert.ErrorContains(t, actual.Error, "failed to send http request")
+	}
+}
@@ -45,7 +45,7 @@ func ExampleNewHTTPLinkCrawler() {
 		crawler.WithLinkCollector(collector.NewJSONLinkCollector(), "application/json", "text/x-json"),
 	)
 
-	links := make(chan string)
+	links := make(chan crawler.Source)
 
 	go func() {
 		examples := []string{
@@ -53,8 +53,8 @@ func ExampleNewHTTPLinkCrawler() {
 			srv2.URL() + "/",
 		}
 
-		for _, link := range examples {
-			links <- link
+		for i, link := range examples {
+			links <- crawler.Source{URL: link, Index: i}
 		}
 
 		close(links)
@@ -21,11 +21,13 @@ import (
 
 	"github.com/nhatthm/go-playground-20221201/internal/collector"
 	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+	"github.com/nhatthm/go-playground-20221201/internal/mockplanner"
 )
 
 const (
-	samplePath = "/path"
-	sampleHTML = "../../resources/fixtures/sample.html"
+	samplePath       = "/path"
+	sampleHTML       = "../../resources/fixtures/sample.html"
+	sampleHTMLBrotli = "../../resources/fixtures/sample.html.br"
 )
 
 func TestLinkCrawler_CrawLinks_OperationCanceled(t *testing.T) {
@@ -33,7 +35,7 @@ func TestLinkCrawler_CrawLinks_OperationCanceled(t *testing.T) {
 
 	shouldCancelCtx := make(chan struct{}, 1)
 	ctxCanceled := make(chan struct{}, 1)
-	links := make(chan string, 2)
+	links := make(chan crawler.Source, 2)
 
 	srv := httpmock.New(func(s *httpmock.Server) {
 		s.ExpectGet(samplePath).
@@ -74,11 +76,11 @@ func TestLinkCrawler_CrawLinks_OperationCanceled(t *testing.T) {
 		defer wg.Done()
 		defer close(links)
 
-		links <- source // This will result in operation canceled error
+		links <- crawler.Source{URL: source} // This will result in operation canceled error
 
 		<-ctxCanceled // Wait until the context is canceled.
 
-		links <- source // This yields no result because the context is canceled.
+		links <- crawler.Source{URL: source, Index: 1} // This yields no result because the context is canceled.
 	}()
 
 	<-shouldCancelCtx
@@ -89,8 +91,9 @@ func TestLinkCrawler_CrawLinks_OperationCanceled(t *testing.T) {
 
 	expected := []crawler.LinkCrawlerResult{
 		{
-			Source: source,
-			Error:  crawler.ErrOperationCanceled,
+			Source:   source,
+			Error:    crawler.ErrOperationCanceled,
+			Attempts: 1,
 		},
 	}
 
@@ -194,6 +197,49 @@ func TestLinkCrawler_CrawLinks_UnsupportedContentType(t *testing.T) {
 	assertLinkCrawlerError(t, results, time.Second, source, `unsupported content type: text/html`)
 }
 
+func TestLinkCrawler_CrawLinks_UnsupportedContentEncoding(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html; charset=utf-8").
+			ReturnHeader("Content-Encoding", "gzip, compress").
+			Return("<html></html>")
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler()
+
+	source := srv.URL() + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerError(t, results, time.Second, source, `unsupported content encoding: compress`)
+}
+
+func TestLinkCrawler_CrawLinks_AcceptEncoding(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html; charset=utf-8").
+			ReturnHeader("Content-Encoding", "identity").
+			Run(func(r *http.Request) ([]byte, error) {
+				return []byte(`<a href="/page">` + r.Header.Get("Accept-Encoding") + `</a>`), nil
+			})
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"))
+
+	source := srv.URL() + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor(srv.URL()+"/page", "gzip, deflate, br")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+}
+
 func TestLinkCrawler_CrawLinks_BrokenResponse(t *testing.T) {
 	t.Parallel()
 
@@ -297,6 +343,42 @@ func TestLinkCrawler_CrawLinks_HTML(t *testing.T) {
 					Run(gzipFile(sampleHTML))
 			},
 		},
+		{
+			scenario: "text/html with deflate",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html; charset=utf-8").
+					ReturnHeader("Content-Encoding", "deflate").
+					Run(zlibFile(sampleHTML))
+			},
+		},
+		{
+			scenario: "text/html with raw deflate",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html; charset=utf-8").
+					ReturnHeader("Content-Encoding", "deflate").
+					Run(flateFile(sampleHTML))
+			},
+		},
+		{
+			scenario: "text/html with brotli",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html; charset=utf-8").
+					ReturnHeader("Content-Encoding", "br").
+					ReturnFile(sampleHTMLBrotli)
+			},
+		},
+		{
+			scenario: "detect content type when missing and content is compressed with brotli",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "").
+					ReturnHeader("Content-Encoding", "BR").
+					ReturnFile(sampleHTMLBrotli)
+			},
+		},
 		{
 			scenario: "application/octet-stream",
 			mockServer: func(s *httpmock.Server) {
@@ -335,23 +417,24 @@ func TestLinkCrawler_CrawLinks_HTML(t *testing.T) {
 
 			assertLinkCrawlerResult(t, results, time.Hour, crawler.LinkCrawlerResult{
 				Source: source,
-				InternalLinks: []string{
-					srv.URL() + "/",
-					srv.URL() + "/absolute/path",
-					srv.URL() + "/relative/path",
-					srv.URL() + "/path#anchor",
-					srv.URL() + "/path?message=hello%20world",
-					srv.URL() + "/",
-					srv.URL() + "/path",
+				InternalLinks: []collector.Link{
+					anchor(srv.URL()+"/", "Root"),
+					anchor(srv.URL()+"/absolute/path", "Absolute path"),
+					anchor(srv.URL()+"/relative/path", "Relative path"),
+					anchor(srv.URL()+"/path#anchor", "Only Anchor"),
+					anchor(srv.URL()+"/path?message=hello%20world", "Encoded"),
+					anchor(srv.URL()+"/", "Dot"),
+					anchor(srv.URL()+"/path", "Empty"),
 				},
-				ExternalLinks: []string{
-					"http://google.com",
-					"http://www.google.com",
-					"https://google.com",
-					"https://www.google.com",
-					"https://example.org/link-is-broken",
-					"http://www.bing.com",
+				ExternalLinks: []collector.Link{
+					anchor("http://google.com", "http without www"),
+					anchor("http://www.google.com", "http with www"),
+					anchor("https://google.com", "https without www"),
+					anchor("https://www.google.com", "https with www"),
+					anchor("https://example.org/link-is-broken", "Link is broken into multiple lines"),
+					anchor("http://www.bing.com", "Bing"),
 				},
+				Attempts: 1,
 			})
 		})
 	}
@@ -378,11 +461,426 @@ func TestLinkCrawler_CrawLinks_HTML_CouldNotParseURL(t *testing.T) {
 	results := c.CrawLinks(context.Background(), sendLinks(source))
 
 	assertLinkCrawlerResult(t, results, time.Hour, crawler.LinkCrawlerResult{
-		Source: source,
-		InternalLinks: []string{
-			srv.URL() + "/",
+		Source:        source,
+		InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Working Link")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+}
+
+func TestLinkCrawler_CrawLinks_HTML_Base(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		base             string
+		expectedInternal func(srvURL string) []collector.Link
+		expectedExternal func(srvURL string) []collector.Link
+	}{
+		{
+			scenario: "no base",
+			expectedInternal: func(srvURL string) []collector.Link {
+				return []collector.Link{anchor(srvURL+"/path/page", "Page"), anchor(srvURL+"/root", "Root"), anchor(srvURL+"/absolute", "Absolute")}
+			},
+			expectedExternal: func(string) []collector.Link {
+				return []collector.Link{}
+			},
+		},
+		{
+			scenario: "relative base",
+			base:     "sub/",
+			expectedInternal: func(srvURL string) []collector.Link {
+				return []collector.Link{anchor(srvURL+"/path/sub/page", "Page"), anchor(srvURL+"/root", "Root"), anchor(srvURL+"/absolute", "Absolute")}
+			},
+			expectedExternal: func(string) []collector.Link {
+				return []collector.Link{}
+			},
+		},
+		{
+			scenario: "base on the same host",
+			base:     "/dir/",
+			expectedInternal: func(srvURL string) []collector.Link {
+				return []collector.Link{anchor(srvURL+"/dir/page", "Page"), anchor(srvURL+"/root", "Root"), anchor(srvURL+"/absolute", "Absolute")}
+			},
+			expectedExternal: func(string) []collector.Link {
+				return []collector.Link{}
+			},
+		},
+		{
+			scenario: "base on another host",
+			base:     "https://cdn.example.com/assets/",
+			expectedInternal: func(srvURL string) []collector.Link {
+				return []collector.Link{anchor(srvURL+"/absolute", "Absolute")}
+			},
+			expectedExternal: func(string) []collector.Link {
+				return []collector.Link{anchor("https://cdn.example.com/assets/page", "Page"), anchor("https://cdn.example.com/root", "Root")}
+			},
+		},
+		{
+			scenario: "base is not http",
+			base:     "ftp://example.com/",
+			expectedInternal: func(srvURL string) []collector.Link {
+				return []collector.Link{anchor(srvURL+"/path/page", "Page"), anchor(srvURL+"/root", "Root"), anchor(srvURL+"/absolute", "Absolute")}
+			},
+			expectedExternal: func(string) []collector.Link {
+				return []collector.Link{}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(func(s *httpmock.Server) {
+				s.ExpectGet("/path/index.html").
+					ReturnHeader("Content-Type", "text/html").
+					Run(func(*http.Request) ([]byte, error) {
+						doc := `<a href="page">Page</a><a href="/root">Root</a><a href="` + s.URL() + `/absolute">Absolute</a>`
+
+						if tc.base != "" {
+							doc = `<head><base href="` + tc.base + `"></head>` + doc
+						}
+
+						return []byte(doc), nil
+					})
+			})(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+			)
+
+			source := srv.URL() + "/path/index.html"
+			results := c.CrawLinks(context.Background(), sendLinks(source))
+
+			assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+				Source:        source,
+				InternalLinks: tc.expectedInternal(srv.URL()),
+				ExternalLinks: tc.expectedExternal(srv.URL()),
+				Attempts:      1,
+			})
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_HTML_Canonical(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/path/index.html").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<head>
+					<base href="/base/">
+					<link rel="canonical" href="canonical">
+					<link rel="alternate" hreflang="fr" href="https://example.com/fr/">
+				</head>
+			`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+	)
+
+	source := srv.URL() + "/path/index.html"
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{{URL: srv.URL() + "/base/canonical", Tag: "link", Type: collector.LinkTypeCanonical, Rel: []string{"canonical"}}},
+		ExternalLinks: []collector.Link{{URL: "https://example.com/fr/", Tag: "link", Type: collector.LinkTypeAlternate, Rel: []string{"alternate"}}},
+		Attempts:      1,
+		Canonical:     srv.URL() + "/base/canonical",
+	})
+}
+
+func TestLinkCrawler_CrawLinks_Recursive(t *testing.T) {
+	t.Parallel()
+
+	// The pages of the same depth are requested concurrently.
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(mockplanner.Unordered())
+
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/page1">Page 1</a>
+				<a href="page2#anchor">Page 2</a>
+				<a href="https://example.com/">Example</a>
+			`)
+
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/">Home</a>
+				<a href="/page2">Page 2</a>
+				<a href="/page3">Page 3</a>
+			`)
+
+		s.ExpectGet("/page2").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/">Home</a>`)
+
+		s.ExpectGet("/page3").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/page4">Page 4 is too deep</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(2),
+		crawler.WithMaxDepth(2),
+	)
+
+	source := srv.URL()
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResults(t, results, time.Second, []crawler.LinkCrawlerResult{
+		{
+			Source:        source,
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/page1", "Page 1"), anchor(srv.URL()+"/page2#anchor", "Page 2")},
+			ExternalLinks: []collector.Link{anchor("https://example.com/", "Example")},
+			Attempts:      1,
+		},
+		{
+			Source:        srv.URL() + "/page1",
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Home"), anchor(srv.URL()+"/page2", "Page 2"), anchor(srv.URL()+"/page3", "Page 3")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			Parent:        source,
+			Depth:         1,
+		},
+		{
+			Source:        srv.URL() + "/page2",
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Home")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			Parent:        source,
+			Depth:         1,
+		},
+		{
+			Source:        srv.URL() + "/page3",
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/page4", "Page 4 is too deep")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			Parent:        srv.URL() + "/page1",
+			Depth:         2,
+		},
+	})
+}
+
+func TestLinkCrawler_CrawLinks_Recursive_SkipAssets(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<link rel="stylesheet" href="/style.css">
+				<a href="/page1">Page 1</a>
+				<img src="/image.png" alt="Image is not crawled">
+			`)
+
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<script src="/script.js"></script>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(2),
+		crawler.WithMaxDepth(2),
+	)
+
+	source := srv.URL() + "/"
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResults(t, results, time.Second, []crawler.LinkCrawlerResult{
+		{
+			Source: source,
+			InternalLinks: []collector.Link{
+				{URL: srv.URL() + "/style.css", Tag: "link", Rel: []string{"stylesheet"}},
+				anchor(srv.URL()+"/page1", "Page 1"),
+				{URL: srv.URL() + "/image.png", Tag: "img"},
+			},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+		},
+		{
+			Source:        srv.URL() + "/page1",
+			InternalLinks: []collector.Link{{URL: srv.URL() + "/script.js", Tag: "script"}},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			Parent:        source,
+			Depth:         1,
+		},
+	})
+}
+
+func TestLinkCrawler_CrawLinks_Recursive_RespectNoFollow(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/page1">Page 1</a>
+				<a href="/page2" rel="nofollow">Page 2 is not crawled</a>
+			`)
+
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<meta name="robots" content="nofollow">
+				<a href="/page3">Page 3 is not crawled</a>
+			`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(2),
+		crawler.WithMaxDepth(3),
+		crawler.WithRespectNoFollow(true),
+	)
+
+	source := srv.URL() + "/"
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResults(t, results, time.Second, []crawler.LinkCrawlerResult{
+		{
+			Source: source,
+			InternalLinks: []collector.Link{
+				anchor(srv.URL()+"/page1", "Page 1"),
+				{URL: srv.URL() + "/page2", Tag: "a", Rel: []string{"nofollow"}, Text: "Page 2 is not crawled"},
+			},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+		},
+		{
+			Source:        srv.URL() + "/page1",
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/page3", "Page 3 is not crawled")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			NoFollow:      true,
+			Parent:        source,
+			Depth:         1,
+		},
+	})
+}
+
+func TestLinkCrawlerResult_RelCounts(t *testing.T) {
+	t.Parallel()
+
+	r := crawler.LinkCrawlerResult{
+		InternalLinks: []collector.Link{
+			{URL: "/followed", Tag: "a"},
+			{URL: "/nofollow", Tag: "a", Rel: []string{"nofollow"}},
+		},
+		ExternalLinks: []collector.Link{
+			{URL: "https://example.com/", Tag: "a", Rel: []string{"sponsored", "nofollow"}},
+			{URL: "https://example.org/", Tag: "a", Rel: []string{"ugc"}},
+			{URL: "https://example.net/", Tag: "a", Rel: []string{"noopener"}},
+		},
+	}
+
+	assert.Equal(t, crawler.RelCounts{Followed: 1, NoFollow: 1}, r.InternalRelCounts())
+	assert.Equal(t, crawler.RelCounts{Followed: 1, NoFollow: 1, Sponsored: 1, UGC: 1}, r.ExternalRelCounts())
+}
+
+func TestLinkCrawler_CrawLinks_MaxPages(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/page1">Page 1</a>`)
+
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/page2">Page 2 is over the budget</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(1),
+		crawler.WithMaxDepth(10),
+		crawler.WithMaxPages(2),
+	)
+
+	source := srv.URL() + "/"
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResults(t, results, time.Second, []crawler.LinkCrawlerResult{
+		{
+			Source:        source,
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/page1", "Page 1")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+		},
+		{
+			Source:        srv.URL() + "/page1",
+			InternalLinks: []collector.Link{anchor(srv.URL()+"/page2", "Page 2 is over the budget")},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         -1,
+			Parent:        source,
+			Depth:         1,
+		},
+	})
+}
+
+func TestLinkCrawler_CrawLinks_SkippedSources(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnHeader("Content-Type", "text/html")
+
+		s.ExpectGet("/page1").
+			ReturnHeader("Content-Type", "text/html")
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(1),
+		crawler.WithMaxDepth(1),
+		crawler.WithMaxPages(2),
+	)
+
+	source := srv.URL() + "/"
+	results := c.CrawLinks(context.Background(), sendLinks(source, source+"#visited", srv.URL()+"/page1", srv.URL()+"/page2"))
+
+	// Every input source has a result, the visited source and the source over the budget are skipped.
+	assertLinkCrawlerResults(t, results, time.Second, []crawler.LinkCrawlerResult{
+		{
+			Source:        source,
+			InternalLinks: []collector.Link{},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+		},
+		{
+			Source:  source + "#visited",
+			Index:   1,
+			Skipped: true,
+		},
+		{
+			Source:        srv.URL() + "/page1",
+			InternalLinks: []collector.Link{},
+			ExternalLinks: []collector.Link{},
+			Attempts:      1,
+			Index:         2,
+		},
+		{
+			Source:  srv.URL() + "/page2",
+			Index:   3,
+			Skipped: true,
 		},
-		ExternalLinks: []string{},
 	})
 }
 
@@ -429,11 +927,10 @@ func TestWithNumWorkers(t *testing.T) {
 			results := c.CrawLinks(context.Background(), sendLinks(source))
 
 			assertLinkCrawlerResult(t, results, time.Hour, crawler.LinkCrawlerResult{
-				Source: source,
-				InternalLinks: []string{
-					srv.URL() + "/",
-				},
-				ExternalLinks: []string{},
+				Source:        source,
+				InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Working Link")},
+				ExternalLinks: []collector.Link{},
+				Attempts:      1,
 			})
 		})
 	}
@@ -2,29 +2,33 @@ package crawler_test
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
 	"github.com/nhatthm/go-playground-20221201/internal/crawler"
 )
 
-func sendLinks(links ...string) <-chan string {
-	ch := make(chan string)
+func sendLinks(links ...string) <-chan crawler.Source {
+	ch := make(chan crawler.Source)
 
 	go func() {
 		defer close(ch)
 
-		for _, link := range links {
-			ch <- link
+		for i, link := range links {
+			ch <- crawler.Source{URL: link, Index: i}
 		}
 	}()
 
@@ -43,6 +47,24 @@ func contextWithDeadline(t *testing.T, d time.Duration) (context.Context, contex
 }
 
 func gzipFile(file string) func(*http.Request) ([]byte, error) {
+	return compressFile(file, func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+}
+
+func zlibFile(file string) func(*http.Request) ([]byte, error) {
+	return compressFile(file, func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriter(w), nil
+	})
+}
+
+func flateFile(file string) func(*http.Request) ([]byte, error) {
+	return compressFile(file, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.DefaultCompression)
+	})
+}
+
+func compressFile(file string, newWriter func(io.Writer) (io.WriteCloser, error)) func(*http.Request) ([]byte, error) {
 	return func(req *http.Request) ([]byte, error) {
 		f, err := os.Open(filepath.Clean(file))
 		if err != nil {
@@ -54,13 +76,17 @@ func gzipFile(file string) func(*http.Request) ([]byte, error) {
 		}()
 
 		buf := new(bytes.Buffer)
-		gz := gzip.NewWriter(buf)
 
-		if _, err := io.Copy(gz, f); err != nil {
+		w, err := newWriter(buf)
+		if err != nil {
+			return nil, fmt.Errorf("could not create compressor: %w", err)
+		}
+
+		if _, err := io.Copy(w, f); err != nil {
 			return nil, fmt.Errorf("could not compress: %w", err)
 		}
 
-		if err := gz.Close(); err != nil {
+		if err := w.Close(); err != nil {
 			return nil, fmt.Errorf("could not close compressor: %w", err)
 		}
 
@@ -98,3 +124,40 @@ func assertLinkCrawlerError(t *testing.T, results <-chan crawler.LinkCrawlerResu
 		assert.EqualError(t, actual.Error, errMsg)
 	}
 }
+
+func assertLinkCrawlerResults(t *testing.T, results <-chan crawler.LinkCrawlerResult, timeout time.Duration, expected []crawler.LinkCrawlerResult) {
+	t.Helper()
+
+	ctx, cancel := contextWithDeadline(t, timeout)
+	defer cancel()
+
+	actual := make([]crawler.LinkCrawlerResult, 0, len(expected))
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Errorf("test timed out")
+
+			return
+
+		case r, ok := <-results:
+			if !ok {
+				// The results come in the completion order.
+				sort.Slice(actual, func(i, j int) bool {
+					return actual[i].Source < actual[j].Source
+				})
+
+				assert.Equal(t, expected, actual)
+
+				return
+			}
+
+			actual = append(actual, r)
+		}
+	}
+}
+
+// anchor creates the link that is collected from the a[href] of an HTML document.
+func anchor(u, text string) collector.Link {
+	return collector.Link{URL: u, Tag: "a", Text: text}
+}
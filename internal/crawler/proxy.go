@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyTransport returns a copy of the transport that sends the requests through a proxy. A nil transport is http.DefaultTransport. The transport is
+// returned as is if it is not an http.Transport, because the proxy could not be set.
+//
+// The configuration starts from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, then the proxy, if not nil, is used for both http and https
+// requests, and the no proxy hosts, if not nil, override the NO_PROXY. The http.Transport dials an `http` or `https` proxy with CONNECT for the https
+// requests, and a `socks5` proxy with the SOCKS5 protocol.
+func proxyTransport(transport http.RoundTripper, proxyURL *url.URL, noProxy []string) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return transport
+	}
+
+	cfg := httpproxy.FromEnvironment()
+
+	if proxyURL != nil {
+		cfg.HTTPProxy = proxyURL.String()
+		cfg.HTTPSProxy = proxyURL.String()
+	}
+
+	if noProxy != nil {
+		cfg.NoProxy = strings.Join(noProxy, ",")
+	}
+
+	proxyFunc := cfg.ProxyFunc()
+
+	t := base.Clone()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	return t
+}
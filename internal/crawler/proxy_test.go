@@ -0,0 +1,367 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+// proxiedHost is a host that does not exist, only the test proxies could reach it.
+const proxiedHost = "crawler.test"
+
+func TestLinkCrawler_CrawLinks_HTTPProxy(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/path2">Path 2</a><a href="https://example.com/">Example</a>`)
+	})(t)
+
+	p := newTestProxy(hostOf(t, srv.URL()))
+	proxyURL := p.httpProxy(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithProxy(proxyURL),
+	)
+
+	source := "http://" + proxiedHost + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor("http://"+proxiedHost+"/path2", "Path 2")},
+		ExternalLinks: []collector.Link{anchor("https://example.com/", "Example")},
+		Attempts:      1,
+	})
+
+	assert.Equal(t, []string{"GET " + source}, p.requests())
+}
+
+func TestLinkCrawler_CrawLinks_HTTPProxy_Connect(t *testing.T) {
+	t.Parallel()
+
+	// The tls server is not trusted by the crawler, the handshake fails after the tunnel is established.
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+
+	t.Cleanup(srv.Close)
+
+	p := newTestProxy(srv.Listener.Addr().String())
+	proxyURL := p.httpProxy(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithProxy(proxyURL),
+	)
+
+	source := "https://" + proxiedHost + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("test timed out")
+
+	case r := <-results:
+		assert.ErrorContains(t, r.Error, "x509: certificate")
+	}
+
+	assert.Equal(t, []string{"CONNECT " + proxiedHost + ":443"}, p.requests())
+}
+
+func TestLinkCrawler_CrawLinks_SOCKS5Proxy(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/path2">Path 2</a>`)
+	})(t)
+
+	p := newTestProxy(hostOf(t, srv.URL()))
+	proxyURL := p.socks5Proxy(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithProxy(proxyURL),
+	)
+
+	source := "http://" + proxiedHost + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor("http://"+proxiedHost+"/path2", "Path 2")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      1,
+	})
+
+	assert.Equal(t, []string{"SOCKS5 " + proxiedHost + ":80"}, p.requests())
+}
+
+func TestLinkCrawler_CrawLinks_NoProxy(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/path2">Path 2</a>`)
+	})(t)
+
+	p := newTestProxy(hostOf(t, srv.URL()))
+	proxyURL := p.httpProxy(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(1),
+		crawler.WithProxy(proxyURL),
+		crawler.WithNoProxy("direct.test", ".internal.test"),
+	)
+
+	proxied := "http://" + proxiedHost + samplePath
+	results := c.CrawLinks(context.Background(), sendLinks(
+		"http://direct.test"+samplePath,
+		"http://www.direct.test"+samplePath,
+		"http://app.internal.test"+samplePath,
+		proxied,
+	))
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	// The hosts without the proxy do not exist.
+	for i := 0; i < 4; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatal("test timed out")
+
+		case r := <-results:
+			if r.Source == proxied {
+				assert.NoError(t, r.Error)
+			} else {
+				assert.Error(t, r.Error, r.Source)
+			}
+		}
+	}
+
+	assert.Equal(t, []string{"GET " + proxied}, p.requests())
+}
+
+// testProxy is a proxy that records the requests, and sends all of them to the target address, whatever their hosts are.
+type testProxy struct {
+	target string
+
+	mu   sync.Mutex
+	reqs []string
+}
+
+func (p *testProxy) record(req string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reqs = append(p.reqs, req)
+}
+
+func (p *testProxy) requests() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.reqs...)
+}
+
+// httpProxy starts an HTTP proxy, which forwards the plain requests and tunnels the CONNECT requests.
+func (p *testProxy) httpProxy(t *testing.T) *url.URL {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			p.record("CONNECT " + r.Host)
+			p.tunnel(w)
+
+			return
+		}
+
+		p.record(r.Method + " " + r.URL.String())
+
+		req := r.Clone(r.Context())
+		req.RequestURI = ""
+		req.URL.Host = p.target
+
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		defer resp.Body.Close() // nolint: errcheck
+
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body) // nolint: errcheck
+	}))
+
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	return u
+}
+
+func (p *testProxy) tunnel(w http.ResponseWriter) {
+	dst, err := net.Dial("tcp", p.target)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	src, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		_ = dst.Close() // nolint: errcheck
+
+		return
+	}
+
+	pipe(src, buf.Reader, dst)
+}
+
+// socks5Proxy starts a SOCKS5 proxy without authentication, which supports only the CONNECT command.
+//
+// See https://www.rfc-editor.org/rfc/rfc1928.
+func (p *testProxy) socks5Proxy(t *testing.T) *url.URL {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = l.Close() // nolint: errcheck
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go p.serveSOCKS5(conn)
+		}
+	}()
+
+	return &url.URL{Scheme: "socks5", Host: l.Addr().String()}
+}
+
+func (p *testProxy) serveSOCKS5(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	// Greeting: version, number of methods, methods. Reply: version, no authentication.
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(r, greeting); err != nil {
+		_ = conn.Close() // nolint: errcheck
+
+		return
+	}
+
+	if _, err := io.ReadFull(r, make([]byte, greeting[1])); err != nil {
+		_ = conn.Close() // nolint: errcheck
+
+		return
+	}
+
+	_, _ = conn.Write([]byte{5, 0}) // nolint: errcheck
+
+	// Request: version, command, reserved, address type, address, port.
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		_ = conn.Close() // nolint: errcheck
+
+		return
+	}
+
+	var host string
+
+	switch header[3] {
+	case 1, 4: // IPv4, IPv6.
+		ip := make([]byte, net.IPv4len)
+		if header[3] == 4 {
+			ip = make([]byte, net.IPv6len)
+		}
+
+		_, _ = io.ReadFull(r, ip) // nolint: errcheck
+		host = net.IP(ip).String()
+
+	case 3: // Domain name.
+		n, _ := r.ReadByte() // nolint: errcheck
+		name := make([]byte, n)
+		_, _ = io.ReadFull(r, name) // nolint: errcheck
+		host = string(name)
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(r, port); err != nil {
+		_ = conn.Close() // nolint: errcheck
+
+		return
+	}
+
+	p.record("SOCKS5 " + net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+
+	dst, err := net.Dial("tcp", p.target)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0}) // nolint: errcheck
+		_ = conn.Close()                                        // nolint: errcheck
+
+		return
+	}
+
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}) // nolint: errcheck
+
+	pipe(conn, r, dst)
+}
+
+func newTestProxy(target string) *testProxy {
+	return &testProxy{target: target}
+}
+
+// pipe copies the data between the source and the destination until one of them is closed. The reader of the source has the buffered data of the source.
+func pipe(src net.Conn, r io.Reader, dst net.Conn) {
+	go func() {
+		_, _ = io.Copy(dst, r) // nolint: errcheck
+		_ = dst.Close()        // nolint: errcheck
+	}()
+
+	_, _ = io.Copy(src, dst) // nolint: errcheck
+	_ = src.Close()          // nolint: errcheck
+}
@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// ErrTooManyRedirects indicates that the source url redirects more than the maximum number of redirects.
+	ErrTooManyRedirects = Error("too many redirects")
+)
+
+const (
+	// defaultMaxRedirects is the default maximum number of redirects to follow. It is the same as the default of http.Client.
+	defaultMaxRedirects = 10
+)
+
+// Redirect is a redirect hop of a request.
+type Redirect struct {
+	// URL is the url that responded with the redirect.
+	URL string
+	// StatusCode is the status code of the redirect response.
+	StatusCode int
+	// Location is the url that the redirect points to.
+	Location string
+}
+
+// redirectPolicy is the redirect policy of a request. It also records the redirect hops of the request.
+type redirectPolicy struct {
+	maxRedirects int
+	follow       bool
+	redirects    []Redirect
+}
+
+// redirectPolicyCtxKey is the context key of the redirect policy.
+type redirectPolicyCtxKey struct{}
+
+// withRedirectPolicy returns a context that carries the redirect policy of the request.
+func withRedirectPolicy(ctx context.Context, p *redirectPolicy) context.Context {
+	return context.WithValue(ctx, redirectPolicyCtxKey{}, p)
+}
+
+// checkRedirect is the redirect policy of the http.Client.CheckRedirect of HTTPLinkCrawler, see authorizeRedirect.
+//
+// It records the redirect hops and applies the redirect policy in the request context. The requests without a policy follow up to defaultMaxRedirects
+// redirects.
+//
+// See https://pkg.go.dev/net/http#Client.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	p, ok := req.Context().Value(redirectPolicyCtxKey{}).(*redirectPolicy)
+	if !ok {
+		p = &redirectPolicy{maxRedirects: defaultMaxRedirects, follow: true}
+	}
+
+	if req.Response != nil {
+		p.redirects = append(p.redirects, Redirect{
+			URL:        via[len(via)-1].URL.String(),
+			StatusCode: req.Response.StatusCode,
+			Location:   req.URL.String(),
+		})
+	}
+
+	if !p.follow {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) > p.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, p.maxRedirects)
+	}
+
+	return nil
+}
+
+// isRedirect checks whether the response is a redirect that could be followed.
+func isRedirect(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return resp.Header.Get("Location") != ""
+	}
+
+	return false
+}
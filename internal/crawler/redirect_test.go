@@ -0,0 +1,124 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_Redirects(t *testing.T) {
+	t.Parallel()
+
+	finalSrv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/home").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`
+				<a href="/page">Page</a>
+				<a href="https://example.com/">Example</a>
+			`)
+	})(t)
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnCode(httpmock.StatusMovedPermanently).
+			ReturnHeader("Location", "/index")
+
+		s.ExpectGet("/index").
+			ReturnCode(httpmock.StatusFound).
+			ReturnHeader("Location", finalSrv.URL()+"/home")
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+	)
+
+	source := srv.URL()
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	// The links are classified against the final url.
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor(finalSrv.URL()+"/page", "Page")},
+		ExternalLinks: []collector.Link{anchor("https://example.com/", "Example")},
+		Attempts:      1,
+		Redirects: []crawler.Redirect{
+			{URL: srv.URL(), StatusCode: 301, Location: srv.URL() + "/index"},
+			{URL: srv.URL() + "/index", StatusCode: 302, Location: finalSrv.URL() + "/home"},
+		},
+	})
+}
+
+func TestLinkCrawler_CrawLinks_TooManyRedirects(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnCode(httpmock.StatusMovedPermanently).
+			ReturnHeader("Location", "/index")
+
+		s.ExpectGet("/index").
+			ReturnCode(httpmock.StatusFound).
+			ReturnHeader("Location", "/home")
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithMaxRedirects(1),
+	)
+
+	source := srv.URL()
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Errorf("test timed out")
+
+	case actual := <-results:
+		expectedRedirects := []crawler.Redirect{
+			{URL: srv.URL(), StatusCode: 301, Location: srv.URL() + "/index"},
+			{URL: srv.URL() + "/index", StatusCode: 302, Location: srv.URL() + "/home"},
+		}
+
+		assert.Equal(t, expectedRedirects, actual.Redirects)
+		assert.ErrorIs(t, actual.Error, crawler.ErrTooManyRedirects)
+	}
+}
+
+func TestLinkCrawler_CrawLinks_NoFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			ReturnCode(httpmock.StatusMovedPermanently).
+			ReturnHeader("Location", "https://www.example.com/").
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="https://www.example.com/">Moved</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithFollowRedirects(false),
+	)
+
+	source := srv.URL()
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:   source,
+		Attempts: 1,
+		Redirects: []crawler.Redirect{
+			{URL: srv.URL(), StatusCode: 301, Location: "https://www.example.com/"},
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import "github.com/nhatthm/go-playground-20221201/internal/collector"
+
+const (
+	relNoFollow  = "nofollow"
+	relSponsored = "sponsored"
+	relUGC       = "ugc"
+)
+
+// RelCounts is the number of links by rel category. A link is counted in every category of its rel keywords, for example: a `nofollow sponsored` link is
+// counted in both NoFollow and Sponsored.
+type RelCounts struct {
+	// Followed is the number of links that are not nofollow, sponsored or ugc.
+	Followed  int
+	NoFollow  int
+	Sponsored int
+	UGC       int
+}
+
+// InternalRelCounts breaks the number of internal links down by rel category.
+func (r LinkCrawlerResult) InternalRelCounts() RelCounts {
+	return countRels(r.InternalLinks)
+}
+
+// ExternalRelCounts breaks the number of external links down by rel category.
+func (r LinkCrawlerResult) ExternalRelCounts() RelCounts {
+	return countRels(r.ExternalLinks)
+}
+
+// countRels counts the links by rel category.
+func countRels(links []collector.Link) RelCounts {
+	var counts RelCounts
+
+	for _, link := range links {
+		followed := true
+
+		if link.HasRel(relNoFollow) {
+			counts.NoFollow++
+			followed = false
+		}
+
+		if link.HasRel(relSponsored) {
+			counts.Sponsored++
+			followed = false
+		}
+
+		if link.HasRel(relUGC) {
+			counts.UGC++
+			followed = false
+		}
+
+		if followed {
+			counts.Followed++
+		}
+	}
+
+	return counts
+}
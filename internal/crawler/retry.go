@@ -0,0 +1,149 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultRetryBaseDelay is the default delay before the first retry.
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// defaultRetryMaxDelay is the default maximum delay between two attempts.
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// RetryPolicy is the policy for retrying the requests that failed due to transient errors, such as connection resets, timeouts, or the status codes 429,
+// 502, 503 and 504.
+//
+// The delay between two attempts grows exponentially from the BaseDelay, with a full jitter, and is capped by the MaxDelay. If the server sends a
+// Retry-After header, the delay is the value of the header, also capped by the MaxDelay.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Zero or one means no retry.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Default value is defaultRetryBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between two attempts. Default value is defaultRetryMaxDelay.
+	MaxDelay time.Duration
+}
+
+// shouldRetry checks whether the request should be retried after the given attempt.
+func (p RetryPolicy) shouldRetry(attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if err != nil {
+		return isTransientError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// delay returns the delay before the next attempt.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	baseDelay, maxDelay := p.BaseDelay, p.MaxDelay
+
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > maxDelay {
+				return maxDelay
+			}
+
+			return d
+		}
+	}
+
+	backoff := maxDelay
+
+	// Avoid overflow by stopping the growth when the backoff reaches the max delay.
+	if shift := attempt - 1; shift < 32 && baseDelay<<shift < maxDelay {
+		backoff = baseDelay << shift
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1)) // nolint: gosec // Jitter does not need a cryptographically secure random number.
+}
+
+// isTransientError checks whether the error is a transient network error, for example: a connection reset or a timeout.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// parseRetryAfter parses the value of the Retry-After header, which is either a number of seconds or an HTTP date.
+//
+// See https://www.rfc-editor.org/rfc/rfc9110#field.retry-after.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+
+	return d, true
+}
+
+// sleep waits for the duration or until the context is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("could not wait for retry: %w", ctx.Err())
+
+	case <-t.C:
+		return nil
+	}
+}
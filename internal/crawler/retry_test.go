@@ -0,0 +1,155 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_CrawLinks_Retry(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		mockServer       func(s *httpmock.Server)
+		expectedAttempts int
+		expectedError    string
+	}{
+		{
+			scenario: "no retry for unexpected status code",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnCode(httpmock.StatusNotFound)
+			},
+			expectedAttempts: 1,
+			expectedError:    "unexpected status code: 404",
+		},
+		{
+			scenario: "success after retries",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnCode(httpmock.StatusServiceUnavailable)
+
+				s.ExpectGet(samplePath).
+					ReturnCode(httpmock.StatusBadGateway)
+
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(`<a href="/">Home</a>`)
+			},
+			expectedAttempts: 3,
+		},
+		{
+			scenario: "success after timeout",
+			mockServer: func(s *httpmock.Server) {
+				// The mock server handles one request at a time, so the first attempt is blocked only until it is timed out, instead of for a fixed
+				// delay that would also hold back the retry.
+				s.ExpectGet(samplePath).
+					Run(func(r *http.Request) ([]byte, error) {
+						<-r.Context().Done()
+
+						return nil, nil
+					})
+
+				s.ExpectGet(samplePath).
+					ReturnHeader("Content-Type", "text/html").
+					Return(`<a href="/">Home</a>`)
+			},
+			expectedAttempts: 2,
+		},
+		{
+			scenario: "max attempts exceeded",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(samplePath).
+					ReturnCode(httpmock.StatusGatewayTimeout).
+					Times(3)
+			},
+			expectedAttempts: 3,
+			expectedError:    "unexpected status code: 504",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(tc.mockServer)(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithClientTimeout(100*time.Millisecond),
+				crawler.WithRetryPolicy(crawler.RetryPolicy{
+					MaxAttempts: 3,
+					BaseDelay:   time.Millisecond,
+					MaxDelay:    10 * time.Millisecond,
+				}),
+			)
+
+			source := srv.URL() + samplePath
+			results := c.CrawLinks(context.Background(), sendLinks(source))
+
+			ctx, cancel := contextWithDeadline(t, time.Second)
+			defer cancel()
+
+			select {
+			case <-ctx.Done():
+				t.Errorf("test timed out")
+
+			case actual := <-results:
+				assert.Equal(t, tc.expectedAttempts, actual.Attempts)
+
+				if tc.expectedError == "" {
+					assert.NoError(t, actual.Error)
+				} else {
+					assert.EqualError(t, actual.Error, tc.expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_Retry_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(samplePath).
+			ReturnCode(httpmock.StatusTooManyRequests).
+			ReturnHeader("Retry-After", "10")
+
+		s.ExpectGet(samplePath).
+			ReturnHeader("Content-Type", "text/html").
+			Return(`<a href="/">Home</a>`)
+	})(t)
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithRetryPolicy(crawler.RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    150 * time.Millisecond, // The Retry-After is capped by the max delay.
+		}),
+	)
+
+	source := srv.URL() + samplePath
+	startTime := time.Now()
+	results := c.CrawLinks(context.Background(), sendLinks(source))
+
+	assertLinkCrawlerResult(t, results, time.Second, crawler.LinkCrawlerResult{
+		Source:        source,
+		InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Home")},
+		ExternalLinks: []collector.Link{},
+		Attempts:      2,
+	})
+
+	assert.GreaterOrEqual(t, time.Since(startTime), 150*time.Millisecond)
+}
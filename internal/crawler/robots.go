@@ -0,0 +1,381 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+const (
+	// ErrDisallowedByRobots indicates that the source url is disallowed by the robots.txt of the host.
+	ErrDisallowedByRobots = Error("disallowed by robots.txt")
+)
+
+const (
+	// robotsPath is the path of the robots.txt file.
+	robotsPath = "/robots.txt"
+	// maxRobotsSize is the maximum size of the robots.txt file to parse. The rest of the file is ignored.
+	// See https://www.rfc-editor.org/rfc/rfc9309#section-2.5.
+	maxRobotsSize = 500 * 1024
+)
+
+// robotsRule is an allow or disallow rule in a robots.txt group.
+type robotsRule struct {
+	allow   bool
+	pattern string
+	match   *regexp.Regexp
+}
+
+// robotsGroup is a group of rules for one or many user agents.
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsTxt is a parsed robots.txt file.
+//
+// See https://www.rfc-editor.org/rfc/rfc9309.
+type robotsTxt struct {
+	groups []*robotsGroup
+	// sitemaps are the urls of the Sitemap lines. They do not belong to any group.
+	sitemaps []string
+}
+
+// groupsFor returns the groups that match the user agent.
+//
+// The groups that match the product token of the user agent take precedence over the `*` groups. Groups that match the same user agent are combined.
+func (r *robotsTxt) groupsFor(userAgent string) []*robotsGroup {
+	product := productToken(userAgent)
+
+	var matched, fallback []*robotsGroup
+
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				fallback = append(fallback, g)
+			} else if productToken(agent) == product {
+				matched = append(matched, g)
+			}
+		}
+	}
+
+	if matched != nil {
+		return matched
+	}
+
+	return fallback
+}
+
+// crawlDelay returns the Crawl-delay for the user agent. It is 0 if there is none.
+//
+// Crawl-delay is not a part of RFC 9309, but it is widely used to ask crawlers to wait between requests to the same host.
+func (r *robotsTxt) crawlDelay(userAgent string) time.Duration {
+	var delay time.Duration
+
+	for _, g := range r.groupsFor(userAgent) {
+		if g.crawlDelay > delay {
+			delay = g.crawlDelay
+		}
+	}
+
+	return delay
+}
+
+// allowed checks whether the user agent is allowed to crawl the url.
+//
+// The most specific rule (the longest pattern) wins. In case of equivalent rules, the allow rule wins. If no rule matches, the url is allowed.
+func (r *robotsTxt) allowed(userAgent string, u url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if path == robotsPath {
+		return true
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed, matchedLen := true, -1
+
+	for _, g := range r.groupsFor(userAgent) {
+		for _, rule := range g.rules {
+			if !rule.match.MatchString(path) {
+				continue
+			}
+
+			if l := len(rule.pattern); l > matchedLen || (l == matchedLen && rule.allow) {
+				allowed, matchedLen = rule.allow, l
+			}
+		}
+	}
+
+	return allowed
+}
+
+// parseRobotsTxt parses a robots.txt file. Unknown and malformed lines are ignored.
+func parseRobotsTxt(r io.Reader) (*robotsTxt, error) {
+	result := &robotsTxt{}
+	s := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+
+	var (
+		group    *robotsGroup
+		hasRules bool
+	)
+
+	for s.Scan() {
+		line := s.Text()
+
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share the same group.
+			if group == nil || hasRules {
+				group = &robotsGroup{}
+				hasRules = false
+
+				result.groups = append(result.groups, group)
+			}
+
+			group.agents = append(group.agents, strings.ToLower(value))
+
+		case "allow", "disallow":
+			if group == nil {
+				continue
+			}
+
+			hasRules = true
+
+			// An empty pattern matches nothing.
+			if value == "" {
+				continue
+			}
+
+			group.rules = append(group.rules, robotsRule{
+				allow:   key == "allow",
+				pattern: value,
+				match:   compileRobotsPattern(value),
+			})
+
+		case "sitemap":
+			if value != "" {
+				result.sitemaps = append(result.sitemaps, value)
+			}
+
+		case "crawl-delay":
+			if group == nil {
+				continue
+			}
+
+			hasRules = true
+
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("could not parse robots.txt: %w", err)
+	}
+
+	return result, nil
+}
+
+// compileRobotsPattern compiles a robots.txt path pattern into a regular expression.
+//
+// The pattern matches the beginning of the path. The special characters are `*` for any sequence of characters and `$` for the end of the path.
+func compileRobotsPattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+
+	sb.WriteString("^")
+
+	for i, part := range strings.Split(pattern, "*") {
+		if i > 0 {
+			sb.WriteString(".*")
+		}
+
+		if strings.HasSuffix(part, "$") && i == strings.Count(pattern, "*") {
+			sb.WriteString(regexp.QuoteMeta(strings.TrimSuffix(part, "$")))
+			sb.WriteString("$")
+
+			continue
+		}
+
+		sb.WriteString(regexp.QuoteMeta(part))
+	}
+
+	return regexp.MustCompile(sb.String())
+}
+
+// productToken returns the lower-cased product token of a user agent, for example: `Mozilla/5.0 (Windows NT 10.0)` becomes `mozilla`.
+func productToken(userAgent string) string {
+	if i := strings.IndexAny(userAgent, "/ "); i >= 0 {
+		userAgent = userAgent[:i]
+	}
+
+	return strings.ToLower(strings.TrimSpace(userAgent))
+}
+
+// robotsEntry is a robots.txt of a host in the cache. The ready channel is closed when the robots.txt is fetched.
+type robotsEntry struct {
+	ready  chan struct{}
+	robots *robotsTxt
+	err    error
+}
+
+// robotsCache caches the robots.txt per host. It is safe for concurrent use, and the robots.txt of a host is fetched only once at a time.
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]*robotsEntry // Key is scheme://host.
+}
+
+// get returns the robots.txt of the host from the cache, or fetches it if it is not in the cache.
+//
+// If the fetch fails, the entry is removed so that the next call can try again.
+func (c *robotsCache) get(ctx context.Context, key string, fetch func() (*robotsTxt, error)) (*robotsTxt, error) {
+	c.mu.Lock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &robotsEntry{ready: make(chan struct{})}
+		c.entries[key] = e
+	}
+
+	c.mu.Unlock()
+
+	if !ok {
+		e.robots, e.err = fetch()
+
+		if e.err != nil {
+			c.mu.Lock()
+			delete(c.entries, key)
+			c.mu.Unlock()
+		}
+
+		close(e.ready)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err() // nolint: wrapcheck // The context error is meaningful, we do not need to wrap it.
+
+	case <-e.ready:
+		return e.robots, e.err
+	}
+}
+
+// newRobotsCache creates a new robots.txt cache.
+func newRobotsCache() *robotsCache {
+	return &robotsCache{
+		entries: make(map[string]*robotsEntry),
+	}
+}
+
+// checkRobots checks whether the source url is allowed by the robots.txt of its host.
+//
+// It returns the Crawl-delay of the host for the user agent, or 0 if there is none.
+func (c HTTPLinkCrawler) checkRobots(ctx context.Context, sourceURL url.URL) (time.Duration, error) {
+	if c.robots == nil {
+		return 0, nil
+	}
+
+	robotsURL := url.URL{Scheme: sourceURL.Scheme, Host: sourceURL.Host, Path: robotsPath}
+
+	robots, err := c.robots.get(ctx, robotsURL.Scheme+"://"+robotsURL.Host, func() (*robotsTxt, error) {
+		return c.fetchRobots(ctx, robotsURL)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !robots.allowed(c.userAgent, sourceURL) {
+		c.log.Error(ctx, "disallowed by robots.txt")
+
+		return 0, ErrDisallowedByRobots
+	}
+
+	return robots.crawlDelay(c.userAgent), nil
+}
+
+// fetchRobots fetches and parses the robots.txt.
+//
+// The request is sent with the per-host limits and the retry policy, like the sources. It follows up to defaultMaxRedirects redirects, no matter the redirect
+// policy of the sources, as specified in RFC 9309.
+//
+// If the robots.txt is not available (4xx), all the urls are allowed. If it is unreachable (5xx), all the urls are disallowed. If the request fails, for
+// example: the host could not be resolved or the request timed out, the error is returned so that it is the error of the source, and the robots.txt is
+// fetched again for the next source of the host.
+//
+// See https://www.rfc-editor.org/rfc/rfc9309#section-2.3.1.
+func (c HTTPLinkCrawler) fetchRobots(ctx context.Context, robotsURL url.URL) (*robotsTxt, error) {
+	ctx = ctxd.AddFields(ctx, "http.robots_url", robotsURL.String())
+	disallowAll := &robotsTxt{groups: []*robotsGroup{{
+		agents: []string{"*"},
+		rules:  []robotsRule{{pattern: "/", match: compileRobotsPattern("/")}},
+	}}}
+
+	resp, _, err := c.sendWithRetry(ctx, http.MethodGet, robotsURL, 0, redirectPolicy{maxRedirects: defaultMaxRedirects, follow: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+
+	defer resp.Body.Close() // nolint: errcheck
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		c.log.Error(ctx, "robots.txt is unreachable, disallow all", "status_code", resp.StatusCode)
+
+		return disallowAll, nil
+
+	case resp.StatusCode >= http.StatusBadRequest:
+		c.log.Debug(ctx, "robots.txt is unavailable, allow all", "status_code", resp.StatusCode)
+
+		return &robotsTxt{}, nil
+	}
+
+	// The custom headers may ask for an encoding that the transport does not decode.
+	if err := decodeBody(resp); err != nil {
+		c.log.Error(ctx, "failed to decode robots.txt, disallow all", "error", err)
+
+		return disallowAll, nil
+	}
+
+	robots, err := parseRobotsTxt(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		c.log.Error(ctx, "failed to parse robots.txt, disallow all", "error", err)
+
+		return disallowAll, nil
+	}
+
+	c.log.Debug(ctx, "fetched robots.txt")
+
+	return robots, nil
+}
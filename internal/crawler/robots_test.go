@@ -0,0 +1,304 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+	"github.com/nhatthm/go-playground-20221201/internal/mockplanner"
+)
+
+func TestLinkCrawler_CrawLinks_RobotsTxt(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario    string
+		mockRobots  func(s *httpmock.Server)
+		expectedErr map[string]error
+	}{
+		{
+			scenario: "robots.txt not found",
+			mockRobots: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnCode(httpmock.StatusNotFound)
+			},
+			expectedErr: map[string]error{},
+		},
+		{
+			scenario: "robots.txt unreachable",
+			mockRobots: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnCode(httpmock.StatusServiceUnavailable)
+			},
+			expectedErr: map[string]error{
+				"/private/secret": crawler.ErrDisallowedByRobots,
+				"/private/public": crawler.ErrDisallowedByRobots,
+				"/search?q=go":    crawler.ErrDisallowedByRobots,
+				"/page.html":      crawler.ErrDisallowedByRobots,
+			},
+		},
+		{
+			scenario: "rules for all user agents",
+			mockRobots: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnHeader("Content-Type", "text/plain").
+					Return(`
+# Comments are ignored.
+User-agent: googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /private # Block the private area.
+Allow: /private/public
+Disallow: /*?q=
+Disallow: /*.html$
+`)
+			},
+			expectedErr: map[string]error{
+				"/private/secret": crawler.ErrDisallowedByRobots,
+				"/search?q=go":    crawler.ErrDisallowedByRobots,
+				"/page.html":      crawler.ErrDisallowedByRobots,
+			},
+		},
+		{
+			scenario: "rules for the product token",
+			mockRobots: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnHeader("Content-Type", "text/plain").
+					Return(`
+User-agent: *
+Disallow: /
+
+User-agent: googlebot
+User-agent: Mozilla
+Disallow: /private/
+`)
+			},
+			expectedErr: map[string]error{
+				"/private/secret": crawler.ErrDisallowedByRobots,
+				"/private/public": crawler.ErrDisallowedByRobots,
+			},
+		},
+	}
+
+	paths := []string{"/page.html", "/private/public", "/private/secret", "/search?q=go"}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// The robots.txt is fetched only once, then the allowed pages are requested concurrently.
+			srv := httpmock.New(func(s *httpmock.Server) {
+				s.WithPlanner(mockplanner.Unordered())
+
+				tc.mockRobots(s)
+
+				for _, path := range paths {
+					if _, ok := tc.expectedErr[path]; ok {
+						continue
+					}
+
+					s.ExpectGet(path).
+						ReturnHeader("Content-Type", "text/html").
+						Return(`<a href="/">Home</a>`)
+				}
+			})(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithNumWorkers(len(paths)),
+				crawler.WithRobotsTxt(true),
+			)
+
+			sources := make([]string, 0, len(paths))
+			expected := make([]crawler.LinkCrawlerResult, 0, len(paths))
+
+			for i, path := range paths {
+				source := srv.URL() + path
+				sources = append(sources, source)
+
+				if err, ok := tc.expectedErr[path]; ok {
+					expected = append(expected, crawler.LinkCrawlerResult{Source: source, Index: i, Error: err})
+
+					continue
+				}
+
+				expected = append(expected, crawler.LinkCrawlerResult{
+					Source:        source,
+					InternalLinks: []collector.Link{anchor(srv.URL()+"/", "Home")},
+					ExternalLinks: []collector.Link{},
+					Attempts:      1,
+					Index:         i,
+				})
+			}
+
+			results := c.CrawLinks(context.Background(), sendLinks(sources...))
+
+			assertLinkCrawlerResults(t, results, time.Second, expected)
+		})
+	}
+}
+
+func TestLinkCrawler_CrawLinks_RobotsTxt_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens on the address of a closed server.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	c := crawler.NewHTTPLinkCrawler(
+		crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+		crawler.WithNumWorkers(1),
+		crawler.WithRobotsTxt(true),
+	)
+
+	results := c.CrawLinks(context.Background(), sendLinks(srv.URL+"/page1", srv.URL+"/page2"))
+
+	ctx, cancel := contextWithDeadline(t, time.Second)
+	defer cancel()
+
+	numResults := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("test timed out")
+
+		case r, ok := <-results:
+			if !ok {
+				assert.Equal(t, 2, numResults)
+
+				return
+			}
+
+			numResults++
+
+			// The sources have the network error, not a disallow-all.
+			var opErr *net.OpError
+
+			assert.NotErrorIs(t, r.Error, crawler.ErrDisallowedByRobots)
+			assert.ErrorContains(t, r.Error, "failed to fetch robots.txt")
+			assert.ErrorAs(t, r.Error, &opErr)
+		}
+	}
+}
+
+func TestLinkCrawler_CrawLinks_RobotsTxt_FetchError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		mockRobots    func(s *httpmock.Server)
+		maxAttempts   int
+		expectedPage1 func(t *testing.T, r crawler.LinkCrawlerResult)
+	}{
+		{
+			scenario: "robots.txt is retried",
+			mockRobots: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnCode(httpmock.StatusServiceUnavailable)
+
+				s.ExpectGet("/robots.txt").
+					Return("User-agent: *\nDisallow: /private\n")
+
+				s.ExpectGet("/page1").
+					ReturnHeader("Content-Type", "text/html").
+					Return(`<a href="/">Home</a>`)
+			},
+			maxAttempts: 2,
+			expectedPage1: func(t *testing.T, r crawler.LinkCrawlerResult) {
+				t.Helper()
+
+				assert.NoError(t, r.Error)
+			},
+		},
+		{
+			scenario: "robots.txt is fetched again after a timeout",
+			mockRobots: func(s *httpmock.Server) {
+				// The mock server handles one request at a time, so the request is blocked only until it is timed out.
+				s.ExpectGet("/robots.txt").
+					Run(func(r *http.Request) ([]byte, error) {
+						<-r.Context().Done()
+
+						return nil, nil
+					})
+
+				s.ExpectGet("/robots.txt").
+					Return("User-agent: *\nDisallow: /private\n")
+			},
+			maxAttempts: 1,
+			expectedPage1: func(t *testing.T, r crawler.LinkCrawlerResult) {
+				t.Helper()
+
+				var netErr net.Error
+
+				require.ErrorAs(t, r.Error, &netErr)
+				assert.True(t, netErr.Timeout())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(func(s *httpmock.Server) {
+				tc.mockRobots(s)
+
+				s.ExpectGet("/page2").
+					ReturnHeader("Content-Type", "text/html").
+					Return(`<a href="/">Home</a>`)
+			})(t)
+
+			c := crawler.NewHTTPLinkCrawler(
+				crawler.WithLinkCollector(collector.NewHTMLLinkCollector(), "text/html"),
+				crawler.WithNumWorkers(1),
+				crawler.WithRobotsTxt(true),
+				crawler.WithClientTimeout(100*time.Millisecond),
+				crawler.WithRetryPolicy(crawler.RetryPolicy{
+					MaxAttempts: tc.maxAttempts,
+					BaseDelay:   time.Millisecond,
+					MaxDelay:    time.Millisecond,
+				}),
+			)
+
+			// The sources are crawled one by one, in order.
+			results := c.CrawLinks(context.Background(), sendLinks(srv.URL()+"/page1", srv.URL()+"/page2", srv.URL()+"/private"))
+
+			ctx, cancel := contextWithDeadline(t, time.Second)
+			defer cancel()
+
+			for i := 0; i < 3; i++ {
+				select {
+				case <-ctx.Done():
+					t.Fatal("test timed out")
+
+				case r := <-results:
+					switch r.Index {
+					case 0:
+						tc.expectedPage1(t, r)
+
+					case 1:
+						assert.NoError(t, r.Error)
+
+					case 2:
+						assert.ErrorIs(t, r.Error, crawler.ErrDisallowedByRobots)
+					}
+				}
+			}
+		})
+	}
+}
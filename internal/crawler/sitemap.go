@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/bool64/ctxd"
+
+	"github.com/nhatthm/go-playground-20221201/internal/collector"
+)
+
+const (
+	// ErrNoSitemap indicates that the robots.txt of the host does not have any Sitemap line.
+	ErrNoSitemap = Error("no sitemap in robots.txt")
+)
+
+const (
+	// maxSitemapSize is the maximum size of an uncompressed sitemap to parse. The rest of the sitemap is ignored.
+	// See https://www.sitemaps.org/protocol.html#index.
+	maxSitemapSize = 50 * 1024 * 1024
+	// maxSitemapDepth is the maximum number of nested sitemap indexes to expand. A sitemap index should not reference another one, the limit is only a
+	// safeguard.
+	maxSitemapDepth = 3
+)
+
+// gzipMagic is the first bytes of a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ReadSitemap reads the urls of the pages in a sitemap, and calls fn for each of them in order. The reading stops at the first error of fn.
+//
+// The nested sitemaps of a sitemap index are expanded, and the gzipped sitemaps, such as `sitemap.xml.gz`, are decompressed. A nested sitemap that could
+// not be read is skipped.
+//
+// If the url is the root or the robots.txt of a site, for example: `https://example.com/`, the sitemaps are discovered from the Sitemap lines of the
+// robots.txt of the site.
+//
+// The requests are sent the same way as the ones for crawling, with the robots.txt, the per-host limits and the retry policy.
+func (c HTTPLinkCrawler) ReadSitemap(ctx context.Context, sitemapURL string, fn func(pageURL string) error) error {
+	ctx = ctxd.AddFields(ctx, "crawler.http.sitemap", sitemapURL)
+
+	u, err := parseURL(sitemapURL)
+	if err != nil {
+		return err
+	}
+
+	sitemaps := []string{u.String()}
+
+	if u.Path == "" || u.Path == "/" || u.Path == robotsPath {
+		if sitemaps, err = c.discoverSitemaps(ctx, *u); err != nil {
+			return err
+		}
+	}
+
+	visited := make(map[string]struct{})
+
+	for _, s := range sitemaps {
+		if err := c.readSitemap(ctx, s, 0, visited, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discoverSitemaps returns the sitemaps in the robots.txt of the site.
+func (c HTTPLinkCrawler) discoverSitemaps(ctx context.Context, siteURL url.URL) ([]string, error) {
+	robots, err := c.fetchRobots(ctx, url.URL{Scheme: siteURL.Scheme, Host: siteURL.Host, Path: robotsPath})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(robots.sitemaps) == 0 {
+		c.log.Error(ctx, "no sitemap in robots.txt")
+
+		return nil, ErrNoSitemap
+	}
+
+	c.log.Debug(ctx, "discovered sitemaps", "crawler.http.sitemaps", robots.sitemaps)
+
+	return robots.sitemaps, nil
+}
+
+// readSitemap reads a sitemap, and expands its nested sitemaps. The sitemaps that have been visited are skipped, so that a loop of sitemap indexes ends.
+//
+// Only the error of the sitemap at depth 0 is returned, the nested sitemaps that could not be read are logged and skipped.
+func (c HTTPLinkCrawler) readSitemap(ctx context.Context, sitemapURL string, depth int, visited map[string]struct{}, fn func(pageURL string) error) error {
+	if _, ok := visited[sitemapURL]; ok {
+		return nil
+	}
+
+	visited[sitemapURL] = struct{}{}
+
+	ctx = ctxd.AddFields(ctx, "crawler.http.sitemap", sitemapURL)
+
+	u, err := parseURL(sitemapURL)
+	if err != nil {
+		return c.skipSitemap(ctx, depth, err)
+	}
+
+	doc, err := c.fetchSitemap(ctx, *u)
+	if err != nil {
+		return c.skipSitemap(ctx, depth, err)
+	}
+
+	c.log.Debug(ctx, "read sitemap", "crawler.http.num_links", len(doc.Links))
+
+	for _, link := range doc.Links {
+		ref, err := url.Parse(link.URL)
+		if err != nil {
+			c.log.Error(ctx, "failed to parse sitemap link", "link", link.URL, "error", err)
+
+			continue
+		}
+
+		linkURL := u.ResolveReference(ref).String()
+
+		if link.Type != collector.LinkTypeSitemap {
+			if err := fn(linkURL); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if depth >= maxSitemapDepth {
+			c.log.Error(ctx, "sitemap is nested too deep, skip", "link", linkURL)
+
+			continue
+		}
+
+		if err := c.readSitemap(ctx, linkURL, depth+1, visited, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipSitemap returns the error if the sitemap is at depth 0, otherwise it logs the error and skips the sitemap. The cancellation is always returned.
+func (c HTTPLinkCrawler) skipSitemap(ctx context.Context, depth int, err error) error {
+	if depth == 0 || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	c.log.Error(ctx, "failed to read nested sitemap, skip", "error", err)
+
+	return nil
+}
+
+// fetchSitemap fetches a sitemap and collects its links. The sitemap is decompressed if it is gzipped, no matter its Content-Type.
+func (c HTTPLinkCrawler) fetchSitemap(ctx context.Context, sitemapURL url.URL) (*collector.Document, error) {
+	resp, _, err := c.doRequest(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() // nolint: errcheck
+
+	// The redirect is not followed, there is no sitemap to read.
+	if isRedirect(resp) {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+
+	br := bufio.NewReader(resp.Body)
+
+	var body io.Reader = br
+
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) { // nolint: errcheck // A short sitemap is not gzipped.
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress sitemap: %w", err)
+		}
+
+		defer gz.Close() // nolint: errcheck
+
+		body = gz
+	}
+
+	doc, err := collector.NewSitemapLinkCollector().GetLinks(io.LimitReader(body, maxSitemapSize))
+	if err != nil {
+		c.log.Error(ctx, "failed to get links", "error", err)
+
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	return doc, nil
+}
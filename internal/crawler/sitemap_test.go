@@ -0,0 +1,194 @@
+//go:build !testsignal
+
+package crawler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/crawler"
+)
+
+func TestLinkCrawler_ReadSitemap(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario    string
+		mockServer  func(s *httpmock.Server)
+		path        string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			scenario: "urlset",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/sitemap.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<urlset><url><loc>/page1</loc></url><url><loc>https://example.com/page2</loc></url></urlset>`)
+			},
+			path:     "/sitemap.xml",
+			expected: []string{"[srv]/page1", "https://example.com/page2"},
+		},
+		{
+			scenario: "sitemap index",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/sitemap.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<sitemapindex>
+						<sitemap><loc>/sitemap-posts.xml.gz</loc></sitemap>
+						<sitemap><loc>/sitemap-missing.xml</loc></sitemap>
+						<sitemap><loc>/sitemap-pages.xml</loc></sitemap>
+						<sitemap><loc>/sitemap.xml</loc></sitemap>
+					</sitemapindex>`)
+
+				s.ExpectGet("/sitemap-posts.xml.gz").
+					ReturnHeader("Content-Type", "application/gzip").
+					Run(gzipped(`<urlset><url><loc>/posts/1</loc></url><url><loc>/posts/2</loc></url></urlset>`))
+
+				s.ExpectGet("/sitemap-missing.xml").
+					ReturnCode(httpmock.StatusNotFound)
+
+				s.ExpectGet("/sitemap-pages.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<urlset><url><loc>/about</loc></url></urlset>`)
+			},
+			path:     "/sitemap.xml",
+			expected: []string{"[srv]/posts/1", "[srv]/posts/2", "[srv]/about"},
+		},
+		{
+			scenario: "discovered from robots.txt",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnHeader("Content-Type", "text/plain").
+					Run(func(r *http.Request) ([]byte, error) {
+						srv := "http://" + r.Host
+
+						return []byte("User-agent: *\nDisallow: /private\n\nSitemap: " + srv + "/sitemap1.xml\nSitemap: " + srv + "/sitemap2.xml\n"), nil
+					})
+
+				s.ExpectGet("/sitemap1.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<urlset><url><loc>/page1</loc></url></urlset>`)
+
+				s.ExpectGet("/sitemap2.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<urlset><url><loc>/page2</loc></url></urlset>`)
+			},
+			path:     "/",
+			expected: []string{"[srv]/page1", "[srv]/page2"},
+		},
+		{
+			scenario: "no sitemap in robots.txt",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/robots.txt").
+					ReturnCode(httpmock.StatusNotFound)
+			},
+			path:        "/robots.txt",
+			expected:    []string{},
+			expectedErr: "no sitemap in robots.txt",
+		},
+		{
+			scenario: "sitemap not found",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/sitemap.xml").
+					ReturnCode(httpmock.StatusNotFound)
+			},
+			path:        "/sitemap.xml",
+			expected:    []string{},
+			expectedErr: "unexpected status code: 404",
+		},
+		{
+			scenario: "broken sitemap",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/sitemap.xml").
+					ReturnHeader("Content-Type", "application/xml").
+					Return(`<urlset><url><loc>/page1</loc></url><url><loc>/page2`)
+			},
+			path:        "/sitemap.xml",
+			expected:    []string{},
+			expectedErr: "failed to get links: could not collect links from sitemap: XML syntax error on line 1: unexpected EOF",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(tc.mockServer)(t)
+
+			c := crawler.NewHTTPLinkCrawler()
+			actual := make([]string, 0)
+
+			err := c.ReadSitemap(context.Background(), srv.URL()+tc.path, func(pageURL string) error {
+				actual = append(actual, pageURL)
+
+				return nil
+			})
+
+			expected := make([]string, 0, len(tc.expected))
+
+			for _, u := range tc.expected {
+				expected = append(expected, strings.ReplaceAll(u, "[srv]", srv.URL()))
+			}
+
+			assert.Equal(t, expected, actual)
+
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestLinkCrawler_ReadSitemap_Stop(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/sitemap.xml").
+			ReturnHeader("Content-Type", "application/xml").
+			Return(`<urlset><url><loc>/page1</loc></url><url><loc>/page2</loc></url></urlset>`)
+	})(t)
+
+	stopErr := errors.New("stop")
+	actual := make([]string, 0)
+
+	err := crawler.NewHTTPLinkCrawler().ReadSitemap(context.Background(), srv.URL()+"/sitemap.xml", func(pageURL string) error {
+		actual = append(actual, pageURL)
+
+		return stopErr
+	})
+
+	require.ErrorIs(t, err, stopErr)
+	assert.Equal(t, []string{srv.URL() + "/page1"}, actual)
+}
+
+// gzipped returns an httpmock handler that responds the gzipped body, without the Content-Encoding header.
+func gzipped(body string) func(*http.Request) ([]byte, error) {
+	return func(*http.Request) ([]byte, error) {
+		buf := new(bytes.Buffer)
+		gz := gzip.NewWriter(buf)
+
+		if _, err := gz.Write([]byte(body)); err != nil {
+			return nil, fmt.Errorf("could not compress: %w", err)
+		}
+
+		if err := gz.Close(); err != nil {
+			return nil, fmt.Errorf("could not compress: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	}
+}
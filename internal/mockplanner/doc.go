@@ -0,0 +1,2 @@
+// Package mockplanner provides the request planners of httpmock for testing the concurrent requests.
+package mockplanner
@@ -0,0 +1,104 @@
+package mockplanner
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/nhatthm/httpmock/planner"
+	"github.com/nhatthm/httpmock/request"
+)
+
+var _ planner.Planner = (*unordered)(nil)
+
+// unordered matches a request with the first remaining expectation that matches it, no matter in which order the expectations are registered.
+type unordered struct {
+	expectations []*request.Request
+
+	mu sync.Mutex
+}
+
+// IsEmpty checks whether the planner has no expectation.
+func (u *unordered) IsEmpty() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return len(u.expectations) == 0
+}
+
+// Expect adds a new expectation.
+func (u *unordered) Expect(expect *request.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.expectations = append(u.expectations, expect)
+}
+
+// Plan returns the first remaining expectation that matches the request. An expectation is removed when it has been called as many times as expected,
+// unless it is expected unlimited times. If no expectation matches, the error of the first one is returned.
+func (u *unordered) Plan(req *http.Request) (*request.Request, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var firstErr error
+
+	for i, expected := range u.expectations {
+		err := planner.MatchRequest(expected, req)
+		if err == nil {
+			u.called(i)
+
+			return expected, nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// called updates the repeatability of the expectation at index i, and removes it if it has been called as many times as expected.
+func (u *unordered) called(i int) {
+	expected := u.expectations[i]
+	times := request.Repeatability(expected)
+
+	if times == 0 {
+		return
+	}
+
+	if times > 1 {
+		request.SetRepeatability(expected, times-1)
+
+		return
+	}
+
+	u.expectations = append(u.expectations[:i:i], u.expectations[i+1:]...)
+}
+
+// Remain returns the remaining expectations.
+func (u *unordered) Remain() []*request.Request {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.expectations
+}
+
+// Reset removes all the expectations.
+func (u *unordered) Reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.expectations = nil
+}
+
+// Unordered creates a new planner that matches the requests in any order, for the servers that receive concurrent requests.
+//
+//	srv := httpmock.New(func(s *httpmock.Server) {
+//		s.WithPlanner(mockplanner.Unordered())
+//
+//		s.ExpectGet("/path1").Return("hello")
+//		s.ExpectGet("/path2").Return("world")
+//	})(t)
+func Unordered() planner.Planner {
+	return &unordered{}
+}
@@ -0,0 +1,47 @@
+//go:build !testsignal
+
+package mockplanner_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nhatthm/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-playground-20221201/internal/mockplanner"
+)
+
+func TestUnordered(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(mockplanner.Unordered())
+
+		s.ExpectGet("/path1").Return("path1")
+		s.ExpectGet("/path2").Return("path2").Twice()
+		s.ExpectGet("/path3").Return("path3")
+	})(t)
+
+	for _, path := range []string{"/path3", "/path2", "/path1", "/path2"} {
+		assert.Equal(t, path[1:], get(t, srv.URL()+path))
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+
+	resp, err := http.Get(url) // nolint: gosec,noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() // nolint: errcheck
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}